@@ -17,6 +17,7 @@ func (c *Case) Run(b *testing.B) {
 		}
 
 		b.Helper()
+		b.ReportAllocs()
 		for i := 0; i < b.N; i++ {
 			b.StopTimer()
 			if pretest := c.Pretest; pretest != nil {
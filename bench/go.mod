@@ -4,13 +4,16 @@ go 1.21.5
 
 replace github.com/jgraeger/jwgo => ../
 
-require github.com/lestrrat-go/jwx/v2 v2.0.18
+require (
+	github.com/golang-jwt/jwt/v5 v5.2.1
+	github.com/jgraeger/jwgo v0.0.0-00010101000000-000000000000
+	github.com/lestrrat-go/jwx/v2 v2.0.18
+)
 
 require (
 	github.com/cristalhq/base64 v0.1.2 // indirect
 	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.2.0 // indirect
 	github.com/goccy/go-json v0.10.2 // indirect
-	github.com/jgraeger/jwgo v0.0.0-00010101000000-000000000000 // indirect
 	github.com/lestrrat-go/blackmagic v1.0.2 // indirect
 	github.com/lestrrat-go/httpcc v1.0.1 // indirect
 	github.com/lestrrat-go/httprc v1.0.4 // indirect
@@ -18,5 +21,6 @@ require (
 	github.com/lestrrat-go/option v1.0.1 // indirect
 	github.com/segmentio/asm v1.2.0 // indirect
 	golang.org/x/crypto v0.16.0 // indirect
+	golang.org/x/sync v0.10.0 // indirect
 	golang.org/x/sys v0.15.0 // indirect
 )
@@ -0,0 +1,89 @@
+package bench_test
+
+import (
+	"testing"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/jgraeger/jwgo/jwa"
+	"github.com/jgraeger/jwgo/jwk"
+	"github.com/jgraeger/jwgo/jws"
+	xjwa "github.com/lestrrat-go/jwx/v2/jwa"
+	xjws "github.com/lestrrat-go/jwx/v2/jws"
+)
+
+func BenchmarkJWSVerify(b *testing.B) {
+	payload := []byte(`{"iss":"https://issuer.example.com","sub":"1234567890","aud":"api","exp":4102444800,"iat":1516239022}`)
+
+	for _, tt := range []struct {
+		alg    jwa.SignatureAlgorithm
+		xalg   xjwa.SignatureAlgorithm
+		method gojwt.SigningMethod
+		kty    jwk.KeyType
+	}{
+		{alg: jwa.HS256, xalg: xjwa.HS256, method: gojwt.SigningMethodHS256, kty: jwk.Oct},
+		{alg: jwa.RS256, xalg: xjwa.RS256, method: gojwt.SigningMethodRS256, kty: jwk.RSA},
+		{alg: jwa.ES256, xalg: xjwa.ES256, method: gojwt.SigningMethodES256, kty: jwk.EC},
+		{alg: jwa.EdDSA, xalg: xjwa.EdDSA, method: gojwt.SigningMethodEdDSA, kty: jwk.OKP},
+	} {
+		tc := tt
+		b.Run(tc.alg.String(), func(b *testing.B) {
+			key, err := jwk.Generate(tc.kty)
+			if err != nil {
+				b.Fatal(err)
+			}
+			token, err := jws.Sign(payload, tc.alg, key)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			// Raw key used by the other libraries
+			raw := key.Raw()
+			if pub, err := jwk.PublicKeyOf(key); err == nil {
+				raw = pub
+			}
+
+			verifier, err := jws.NewVerifier(tc.alg, key)
+			if err != nil {
+				b.Fatal(err)
+			}
+			dst := make([]byte, 0, len(payload))
+
+			parser := gojwt.NewParser(gojwt.WithValidMethods([]string{tc.method.Alg()}), gojwt.WithoutClaimsValidation())
+			keyFunc := func(*gojwt.Token) (any, error) { return raw, nil }
+			s := string(token)
+
+			for _, c := range []Case{
+				{
+					Name: "jwgo/jws.Verifier",
+					Test: func(b *testing.B) error {
+						_, err := verifier.Verify(dst, token)
+						return err
+					},
+				},
+				{
+					Name: "jwgo/jws.Verify",
+					Test: func(b *testing.B) error {
+						_, err := jws.Verify(token, jws.StaticKey(key))
+						return err
+					},
+				},
+				{
+					Name: "jwx/v2/jws.Verify",
+					Test: func(b *testing.B) error {
+						_, err := xjws.Verify(token, xjws.WithKey(tc.xalg, raw))
+						return err
+					},
+				},
+				{
+					Name: "golang-jwt/v5.Parse",
+					Test: func(b *testing.B) error {
+						_, err := parser.Parse(s, keyFunc)
+						return err
+					},
+				},
+			} {
+				c.Run(b)
+			}
+		})
+	}
+}
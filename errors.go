@@ -2,7 +2,6 @@ package jwgo
 
 import (
 	"errors"
-	"fmt"
 )
 
 var (
@@ -10,8 +9,3 @@ var (
 	ErrTokenMalformed = errors.New("token is malformed")
 	ErrKeyMalformed   = errors.New("key is malformed")
 )
-
-// internal errors
-var (
-	errInvalidSegmentCount = fmt.Errorf("%w: token contains an invalid number of segments", ErrTokenMalformed)
-)
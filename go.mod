@@ -6,6 +6,7 @@ require (
 	github.com/cristalhq/base64 v0.1.2
 	github.com/goccy/go-json v0.10.2
 	github.com/stretchr/testify v1.8.4
+	golang.org/x/sync v0.10.0
 )
 
 require (
@@ -0,0 +1,57 @@
+// Package jsonenc contains helpers to write JSON without reflection.
+package jsonenc
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+const hexDigits = "0123456789abcdef"
+
+// WriteString writes s as a quoted and escaped JSON string into buf.
+func WriteString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c >= utf8.RuneSelf {
+			// Multi-byte UTF-8 sequences are valid in JSON strings, except for invalid ones.
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r == utf8.RuneError && size == 1 {
+				buf.WriteString(s[start:i])
+				buf.WriteString("\ufffd")
+				start = i + size
+			}
+			i += size
+			continue
+		}
+
+		if c >= 0x20 && c != '"' && c != '\\' {
+			i++
+			continue
+		}
+
+		buf.WriteString(s[start:i])
+		switch c {
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			buf.WriteString(`\u00`)
+			buf.WriteByte(hexDigits[c>>4])
+			buf.WriteByte(hexDigits[c&0xF])
+		}
+		i++
+		start = i
+	}
+
+	buf.WriteString(s[start:])
+	buf.WriteByte('"')
+}
@@ -38,7 +38,7 @@ func (ka KeyAlgorithm) Valid() bool {
 func KeyAlgorithmFrom[T SignatureAlgorithm | string](v T) (KeyAlgorithm, error) {
 	alg := KeyAlgorithm(v)
 	if !alg.Valid() {
-		return "", fmt.Errorf("invalid key algorithm %q", v)
+		return "", unknownAlgErr(string(v))
 	}
 	return alg, nil
 }
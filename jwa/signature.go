@@ -23,9 +23,9 @@ const (
 	// RSASSA-PSS using SHA256 and MGF1-SHA256
 	PS256 SignatureAlgorithm = "PS256"
 	// RSASSA-PSS using SHA384 and MGF1-SHA384
-	P384 SignatureAlgorithm = "PS384"
+	PS384 SignatureAlgorithm = "PS384"
 	// RSASSA-PSS using SHA512 and MGF1-SHA512
-	P512 SignatureAlgorithm = "PS512"
+	PS512 SignatureAlgorithm = "PS512"
 
 	// ECDSA using P-256 and SHA-256
 	ES256 SignatureAlgorithm = "ES256"
@@ -40,6 +40,13 @@ const (
 	EdDSA SignatureAlgorithm = "EdDSA"
 )
 
+const (
+	// Deprecated: Use PS384.
+	P384 = PS384
+	// Deprecated: Use PS512.
+	P512 = PS512
+)
+
 var allSignatureAlgorithms = map[string]SignatureAlgorithm{
 	string(HS256):  HS256,
 	string(HS384):  HS384,
@@ -48,8 +55,8 @@ var allSignatureAlgorithms = map[string]SignatureAlgorithm{
 	string(RS384):  RS384,
 	string(RS512):  RS512,
 	string(PS256):  PS256,
-	string(P384):   P384,
-	string(P512):   P512,
+	string(PS384):  PS384,
+	string(PS512):  PS512,
 	string(ES256):  ES256,
 	string(ES256K): ES256K,
 	string(ES384):  ES384,
@@ -0,0 +1,182 @@
+package jwk
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// DefaultRefreshInterval is the maximum time a key set is cached, unless set by WithRefreshInterval.
+	DefaultRefreshInterval = time.Hour
+	// DefaultMinRefreshInterval is the minimum time between two fetches, unless set by WithMinRefreshInterval.
+	DefaultMinRefreshInterval = time.Minute
+)
+
+// Cache keeps a key set loaded from a URL up to date.
+//
+// The key set is refreshed in the background when it expires according to the
+// Cache-Control or Expires headers of the response, but at least every refresh interval.
+// Fetches are conditional if the response has an ETag. If a refresh fails,
+// the last key set that was fetched successfully is used.
+type Cache struct {
+	ctx     context.Context
+	fetcher *Fetcher
+	url     string
+	cfg     cacheConfig
+
+	group singleflight.Group
+
+	mu        sync.RWMutex
+	set       *Set
+	etag      string
+	expires   time.Time
+	lastFetch time.Time
+	err       error
+}
+
+// NewCache creates a cache for the key set at the URL and starts to refresh
+// it in the background until ctx is done.
+func NewCache(ctx context.Context, f *Fetcher, url string, opts ...CacheOption) *Cache {
+	c := &Cache{
+		ctx:     ctx,
+		fetcher: f,
+		url:     url,
+		cfg:     newCacheConfig(opts),
+	}
+	go c.run()
+	return c
+}
+
+// Get returns the cached key set. If the key set has expired or was not fetched yet,
+// it is refreshed. If the refresh fails, the last key set is returned if there is one.
+func (c *Cache) Get(ctx context.Context) (*Set, error) {
+	c.mu.RLock()
+	set, now := c.set, time.Now()
+	fresh := now.Before(c.expires)
+	limited := now.Sub(c.lastFetch) < c.cfg.minInterval
+	c.mu.RUnlock()
+
+	if set != nil && (fresh || limited) {
+		return set, nil
+	}
+
+	refreshed, err := c.Refresh(ctx)
+	if err != nil {
+		if set != nil {
+			return set, nil
+		}
+		return nil, err
+	}
+	return refreshed, nil
+}
+
+// Refresh fetches the key set. Concurrent refreshes share a single request.
+func (c *Cache) Refresh(ctx context.Context) (*Set, error) {
+	ch := c.group.DoChan(c.url, func() (any, error) {
+		return c.fetch()
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-ch:
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		return r.Val.(*Set), nil //nolint:forcetypeassert
+	}
+}
+
+// LookupKeyID returns the key with the key ID. If the key set contains no such key,
+// it is refreshed once, unless the last fetch was less than the minimum refresh interval ago.
+func (c *Cache) LookupKeyID(ctx context.Context, kid string) (Key, error) {
+	set, err := c.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if k, ok := set.LookupKeyID(kid); ok {
+		return k, nil
+	}
+
+	c.mu.RLock()
+	limited := time.Since(c.lastFetch) < c.cfg.minInterval
+	c.mu.RUnlock()
+	if limited {
+		return nil, keyNotFoundErr(kid)
+	}
+
+	set, err = c.Refresh(ctx)
+	if err != nil {
+		return nil, errors.Join(keyNotFoundErr(kid), err)
+	}
+	if k, ok := set.LookupKeyID(kid); ok {
+		return k, nil
+	}
+	return nil, keyNotFoundErr(kid)
+}
+
+// Err returns the error of the last refresh, or nil if it succeeded.
+func (c *Cache) Err() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.err
+}
+
+func (c *Cache) run() {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		_, _ = c.Refresh(c.ctx)
+
+		c.mu.RLock()
+		next := c.expires
+		if retry := c.lastFetch.Add(c.cfg.minInterval); c.err != nil || retry.After(next) {
+			next = retry
+		}
+		c.mu.RUnlock()
+
+		timer.Reset(time.Until(next))
+	}
+}
+
+func (c *Cache) fetch() (*Set, error) {
+	c.mu.RLock()
+	etag := c.etag
+	c.mu.RUnlock()
+
+	res, err := c.fetcher.fetch(c.ctx, c.url, etag)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.lastFetch = now
+	c.err = err
+	if err != nil {
+		return nil, err
+	}
+
+	if res.set != nil {
+		c.set = res.set
+		c.etag = res.etag
+	}
+
+	lifetime := c.cfg.interval
+	if res.hasLifetime {
+		lifetime = min(lifetime, res.lifetime)
+	}
+	c.expires = now.Add(max(lifetime, c.cfg.minInterval))
+
+	return c.set, nil
+}
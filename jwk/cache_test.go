@@ -0,0 +1,225 @@
+package jwk_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jgraeger/jwgo/jwk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jwksServer serves a key set with the given key IDs.
+type jwksServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	kids     []string
+	status   int
+	header   http.Header
+	requests atomic.Int32
+	notMod   atomic.Int32
+	block    chan struct{}
+}
+
+func newJWKSServer(t *testing.T, kids ...string) *jwksServer {
+	t.Helper()
+
+	s := &jwksServer{kids: kids, status: http.StatusOK, header: http.Header{}}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		if s.block != nil {
+			<-s.block
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		for k, v := range s.header {
+			w.Header()[k] = v
+		}
+		if s.status != http.StatusOK {
+			w.WriteHeader(s.status)
+			return
+		}
+
+		etag := fmt.Sprintf("%q", fmt.Sprint(s.kids))
+		if r.Header.Get("If-None-Match") == etag {
+			s.notMod.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", etag)
+		fmt.Fprint(w, `{"keys":[`)
+		for i, kid := range s.kids {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"kty":"oct","kid":%q,"k":"c3VwZXJzZWNyZXQ"}`, kid)
+		}
+		fmt.Fprint(w, `]}`)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) set(kids []string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.kids, s.status = kids, status
+}
+
+func TestFetcher(t *testing.T) {
+	t.Parallel()
+
+	srv := newJWKSServer(t, "a", "b")
+	f := jwk.NewFetcher(srv.Client())
+
+	set, err := f.Fetch(context.Background(), srv.URL)
+	require.NoError(t, err)
+	assert.Equal(t, 2, set.Len())
+
+	_, err = f.Fetch(context.Background(), "http://"+srv.Listener.Addr().String())
+	assert.Error(t, err, "URLs must use https")
+
+	_, err = jwk.NewFetcher(srv.Client(), jwk.WithMaxInputSize(16)).Fetch(context.Background(), srv.URL)
+	assert.ErrorIs(t, err, jwk.ErrInputTooLarge)
+
+	srv.set(nil, http.StatusInternalServerError)
+	_, err = f.Fetch(context.Background(), srv.URL)
+	assert.Error(t, err)
+}
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("conditional background refresh", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newJWKSServer(t, "a")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		c := jwk.NewCache(ctx, jwk.NewFetcher(srv.Client()), srv.URL,
+			jwk.WithRefreshInterval(20*time.Millisecond),
+			jwk.WithMinRefreshInterval(time.Millisecond),
+		)
+
+		set, err := c.Get(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, set.Len())
+
+		assert.Eventually(t, func() bool { return srv.notMod.Load() >= 2 }, time.Second, 5*time.Millisecond)
+
+		srv.set([]string{"a", "b"}, http.StatusOK)
+		assert.Eventually(t, func() bool {
+			set, err := c.Get(ctx)
+			return err == nil && set.Len() == 2
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("cache lifetime from headers", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newJWKSServer(t, "a")
+		srv.header.Set("Cache-Control", "public, max-age=0")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		c := jwk.NewCache(ctx, jwk.NewFetcher(srv.Client()), srv.URL,
+			jwk.WithMinRefreshInterval(10*time.Millisecond),
+		)
+		_, err := c.Get(ctx)
+		require.NoError(t, err)
+
+		// The default refresh interval is an hour, so refreshes are caused by max-age
+		assert.Eventually(t, func() bool { return srv.requests.Load() >= 3 }, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("concurrent refreshes", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newJWKSServer(t, "a")
+		srv.block = make(chan struct{})
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		c := jwk.NewCache(ctx, jwk.NewFetcher(srv.Client()), srv.URL)
+
+		// Wait for the initial background fetch
+		require.Eventually(t, func() bool { return srv.requests.Load() == 1 }, time.Second, time.Millisecond)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				set, err := c.Refresh(ctx)
+				assert.NoError(t, err)
+				assert.Equal(t, 1, set.Len())
+			}()
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		close(srv.block)
+		wg.Wait()
+
+		assert.EqualValues(t, 1, srv.requests.Load())
+	})
+
+	t.Run("last good set on failure", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newJWKSServer(t, "a")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		c := jwk.NewCache(ctx, jwk.NewFetcher(srv.Client()), srv.URL, jwk.WithMinRefreshInterval(0))
+		_, err := c.Get(ctx)
+		require.NoError(t, err)
+
+		srv.set(nil, http.StatusServiceUnavailable)
+		_, err = c.Refresh(ctx)
+		require.Error(t, err)
+		assert.Error(t, c.Err())
+
+		set, err := c.Get(ctx)
+		require.NoError(t, err)
+		_, ok := set.LookupKeyID("a")
+		assert.True(t, ok)
+	})
+
+	t.Run("unknown key ID", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newJWKSServer(t, "a")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		c := jwk.NewCache(ctx, jwk.NewFetcher(srv.Client()), srv.URL, jwk.WithMinRefreshInterval(50*time.Millisecond))
+		_, err := c.LookupKeyID(ctx, "a")
+		require.NoError(t, err)
+
+		// Rate limited by the minimum refresh interval
+		srv.set([]string{"a", "b"}, http.StatusOK)
+		requests := srv.requests.Load()
+		_, err = c.LookupKeyID(ctx, "b")
+		assert.ErrorIs(t, err, jwk.ErrKeyNotFound)
+		assert.Equal(t, requests, srv.requests.Load())
+
+		time.Sleep(60 * time.Millisecond)
+		k, err := c.LookupKeyID(ctx, "b")
+		require.NoError(t, err)
+		assert.Equal(t, "b", k.ID())
+
+		_, err = c.LookupKeyID(ctx, "c")
+		assert.ErrorIs(t, err, jwk.ErrKeyNotFound)
+	})
+}
@@ -0,0 +1,263 @@
+package jwk
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"fmt"
+	"math/big"
+
+	"github.com/jgraeger/jwgo/internal/base64"
+	"github.com/jgraeger/jwgo/jwa"
+)
+
+// FromCrypto creates a key from a Go crypto key.
+// Supported are *rsa.PrivateKey, *rsa.PublicKey, *ecdsa.PrivateKey, *ecdsa.PublicKey,
+// ed25519.PrivateKey, ed25519.PublicKey, *ecdh.PrivateKey, *ecdh.PublicKey
+// and []byte for symmetric keys.
+func FromCrypto(key any, opts ...KeyOption) (Key, error) {
+	cfg := newKeyConfig(opts)
+
+	k, err := fromCrypto(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.apply(k); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// PublicKeyOf returns the public Go crypto key of k,
+// e.g. *rsa.PublicKey for both RSA private and public keys.
+func PublicKeyOf(k Key) (crypto.PublicKey, error) {
+	pub, ok := k.(interface{ Public() crypto.PublicKey })
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoPublicKey, k.Type())
+	}
+	return pub.Public(), nil
+}
+
+func fromCrypto(key any) (Key, error) {
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		priv, err := copyRSAPrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		return &RSAPrivateKey{Header: Header{Kty: RSA}, rsa: priv}, nil
+	case *rsa.PublicKey:
+		if key == nil || key.N == nil {
+			return nil, fmt.Errorf("%w: rsa: missing modulus", ErrMalformedKey)
+		}
+		return &RSAPublicKey{Header: Header{Kty: RSA}, rsa: rsa.PublicKey{N: cloneInt(key.N), E: key.E}}, nil
+	case *ecdsa.PrivateKey:
+		if key == nil || key.D == nil {
+			return nil, fmt.Errorf("%w: ec: missing private key", ErrMalformedKey)
+		}
+		pub, err := copyECDSAPublicKey(&key.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		return &ECPrivateKey{Header: Header{Kty: EC}, ecdsa: &ecdsa.PrivateKey{PublicKey: *pub, D: cloneInt(key.D)}}, nil
+	case *ecdsa.PublicKey:
+		pub, err := copyECDSAPublicKey(key)
+		if err != nil {
+			return nil, err
+		}
+		return &ECPublicKey{Header: Header{Kty: EC}, ecdsa: pub}, nil
+	case ed25519.PrivateKey:
+		if len(key) != ed25519.PrivateKeySize {
+			return nil, fmt.Errorf("%w: okp: invalid private key size %d", ErrMalformedKey, len(key))
+		}
+		//nolint:forcetypeassert
+		return &OKPPrivateKey{
+			Header: Header{Kty: OKP},
+			crv:    Ed25519,
+			x:      key.Public().(ed25519.PublicKey),
+			d:      key.Seed(),
+		}, nil
+	case ed25519.PublicKey:
+		if len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%w: okp: invalid public key size %d", ErrMalformedKey, len(key))
+		}
+		return &OKPPublicKey{Header: Header{Kty: OKP}, crv: Ed25519, x: append([]byte(nil), key...)}, nil
+	case *ecdh.PrivateKey:
+		if key == nil {
+			return nil, fmt.Errorf("%w: ecdh: missing private key", ErrMalformedKey)
+		}
+		return fromECDHPrivateKey(key)
+	case *ecdh.PublicKey:
+		if key == nil {
+			return nil, fmt.Errorf("%w: ecdh: missing public key", ErrMalformedKey)
+		}
+		return fromECDHPublicKey(key)
+	case []byte:
+		if len(key) == 0 {
+			return nil, fmt.Errorf("%w: oct: empty key", ErrMalformedKey)
+		}
+		return &SymmetricKey{Header: Header{Kty: Oct}, k: append([]byte(nil), key...)}, nil
+	default:
+		return nil, unknownKeyTypeErr(fmt.Sprintf("%T", key))
+	}
+}
+
+// copyRSAPrivateKey copies key, so later changes by the caller do not affect the JWK.
+func copyRSAPrivateKey(key *rsa.PrivateKey) (*rsa.PrivateKey, error) {
+	if key == nil || key.N == nil || key.D == nil {
+		return nil, fmt.Errorf("%w: rsa: missing private key", ErrMalformedKey)
+	}
+	if len(key.Primes) < 2 {
+		return nil, fmt.Errorf("%w: rsa: expected at least 2 primes, got %d", ErrMalformedKey, len(key.Primes))
+	}
+
+	priv := &rsa.PrivateKey{
+		PublicKey: rsa.PublicKey{N: cloneInt(key.N), E: key.E},
+		D:         cloneInt(key.D),
+		Primes:    make([]*big.Int, len(key.Primes)),
+	}
+	for i, p := range key.Primes {
+		if p == nil {
+			return nil, fmt.Errorf("%w: rsa: missing prime %d", ErrMalformedKey, i)
+		}
+		priv.Primes[i] = cloneInt(p)
+	}
+
+	if key.Precomputed.Dp != nil {
+		priv.Precomputed.Dp = cloneInt(key.Precomputed.Dp)
+		priv.Precomputed.Dq = cloneInt(key.Precomputed.Dq)
+		priv.Precomputed.Qinv = cloneInt(key.Precomputed.Qinv)
+		for _, v := range key.Precomputed.CRTValues {
+			priv.Precomputed.CRTValues = append(priv.Precomputed.CRTValues, rsa.CRTValue{
+				Exp:   cloneInt(v.Exp),
+				Coeff: cloneInt(v.Coeff),
+				R:     cloneInt(v.R),
+			})
+		}
+		// Restores the internal values used for faster private key operations
+		priv.Precompute()
+	}
+	return priv, nil
+}
+
+// copyECDSAPublicKey copies key, so later changes by the caller do not affect the JWK.
+func copyECDSAPublicKey(key *ecdsa.PublicKey) (*ecdsa.PublicKey, error) {
+	if key == nil || key.Curve == nil || key.X == nil || key.Y == nil {
+		return nil, fmt.Errorf("%w: ec: missing public key", ErrMalformedKey)
+	}
+	if _, ok := Curve(key.Curve.Params().Name).ellipticCurve(); !ok {
+		return nil, unknownCurveErr(key.Curve.Params().Name)
+	}
+	return &ecdsa.PublicKey{Curve: key.Curve, X: cloneInt(key.X), Y: cloneInt(key.Y)}, nil
+}
+
+func cloneInt(x *big.Int) *big.Int {
+	if x == nil {
+		return nil
+	}
+	return new(big.Int).Set(x)
+}
+
+func fromECDHPrivateKey(key *ecdh.PrivateKey) (Key, error) {
+	if key.Curve() == ecdh.X25519() {
+		return &OKPPrivateKey{
+			Header: Header{Kty: OKP},
+			crv:    X25519,
+			x:      key.PublicKey().Bytes(),
+			d:      key.Bytes(),
+		}, nil
+	}
+
+	pub, err := fromECDHPublicKey(key.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint:forcetypeassert
+	return &ECPrivateKey{
+		Header: Header{Kty: EC},
+		ecdsa: &ecdsa.PrivateKey{
+			PublicKey: *pub.(*ECPublicKey).ecdsa,
+			D:         new(big.Int).SetBytes(key.Bytes()),
+		},
+	}, nil
+}
+
+func fromECDHPublicKey(key *ecdh.PublicKey) (Key, error) {
+	var crv Curve
+	switch key.Curve() {
+	case ecdh.X25519():
+		return &OKPPublicKey{Header: Header{Kty: OKP}, crv: X25519, x: key.Bytes()}, nil
+	case ecdh.P256():
+		crv = P256
+	case ecdh.P384():
+		crv = P384
+	case ecdh.P521():
+		crv = P521
+	default:
+		return nil, unknownCurveErr(fmt.Sprint(key.Curve()))
+	}
+
+	// NIST curve points are encoded in uncompressed form
+	curve, _ := crv.ellipticCurve()
+	size := crv.coordinateSize()
+	point := key.Bytes()
+
+	return &ECPublicKey{
+		Header: Header{Kty: EC},
+		ecdsa: &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(point[1 : 1+size]),
+			Y:     new(big.Int).SetBytes(point[1+size:]),
+		},
+	}, nil
+}
+
+// apply validates the configured claims and sets them on the key.
+func (c keyConfig) apply(k Key) error {
+	h := k.(interface{ header() *Header }).header() //nolint:forcetypeassert
+
+	if c.alg != "" {
+		if _, err := jwa.KeyAlgorithmFrom(c.alg.String()); err != nil {
+			return fmt.Errorf("%w: %w", ErrMalformedKey, err)
+		}
+	}
+	if c.use != Unspecified && !c.use.valid() {
+		return unknownKeyUseErr(string(c.use))
+	}
+	if sk, ok := k.(*SymmetricKey); ok {
+		if minSize := minSymmetricKeySize(c.alg); len(sk.k) < minSize {
+			return fmt.Errorf("%w: oct: key of %d bytes is too short for %s", ErrMalformedKey, len(sk.k), c.alg)
+		}
+	}
+
+	h.Alg = c.alg
+	h.Use = c.use
+	h.Ops = c.ops
+	h.Kid = c.kid
+	h.X5C = c.certs
+
+	if err := h.validateKeyOps(); err != nil {
+		return err
+	}
+	if err := validateX509(k); err != nil {
+		return err
+	}
+
+	if h.Kid == "" && c.thumbprintHash != 0 {
+		if !c.thumbprintHash.Available() {
+			return fmt.Errorf("thumbprint hash %s is not available", c.thumbprintHash)
+		}
+
+		tp, err := k.Thumbprint(c.thumbprintHash)
+		if err != nil {
+			return err
+		}
+		h.Kid = base64.RawURLEncoding.EncodeToString(tp)
+	}
+
+	return nil
+}
@@ -0,0 +1,329 @@
+package jwk_test
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"testing"
+
+	"github.com/jgraeger/jwgo/jwa"
+	"github.com/jgraeger/jwgo/jwk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromCrypto(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	xKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	ecdhKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name     string
+		key      any
+		expected any
+		kty      jwk.KeyType
+		alg      jwa.SignatureAlgorithm
+	}{
+		{name: "*rsa.PrivateKey", key: rsaKey, expected: &jwk.RSAPrivateKey{}, kty: jwk.RSA, alg: jwa.RS256},
+		{name: "*rsa.PublicKey", key: &rsaKey.PublicKey, expected: &jwk.RSAPublicKey{}, kty: jwk.RSA, alg: jwa.RS256},
+		{name: "*ecdsa.PrivateKey", key: ecKey, expected: &jwk.ECPrivateKey{}, kty: jwk.EC, alg: jwa.ES384},
+		{name: "*ecdsa.PublicKey", key: &ecKey.PublicKey, expected: &jwk.ECPublicKey{}, kty: jwk.EC, alg: jwa.ES384},
+		{name: "ed25519.PrivateKey", key: edKey, expected: &jwk.OKPPrivateKey{}, kty: jwk.OKP, alg: jwa.EdDSA},
+		{name: "ed25519.PublicKey", key: edPub, expected: &jwk.OKPPublicKey{}, kty: jwk.OKP, alg: jwa.EdDSA},
+		{name: "*ecdh.PrivateKey X25519", key: xKey, expected: &jwk.OKPPrivateKey{}, kty: jwk.OKP},
+		{name: "*ecdh.PublicKey X25519", key: xKey.PublicKey(), expected: &jwk.OKPPublicKey{}, kty: jwk.OKP},
+		{name: "*ecdh.PrivateKey P-256", key: ecdhKey, expected: &jwk.ECPrivateKey{}, kty: jwk.EC, alg: jwa.ES256},
+		{name: "[]byte", key: []byte("0123456789abcdef0123456789abcdef"), expected: &jwk.SymmetricKey{}, kty: jwk.Oct, alg: jwa.HS256},
+	} {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := []jwk.KeyOption{jwk.WithThumbprintKeyID(crypto.SHA256)}
+			if tc.alg != "" {
+				opts = append(opts, jwk.WithAlgorithm(jwa.KeyAlgorithmMustFrom(tc.alg)))
+			}
+
+			key, err := jwk.FromCrypto(tc.key, opts...)
+			require.NoError(t, err)
+			assert.IsType(t, tc.expected, key)
+			assert.Equal(t, tc.kty, key.Type())
+
+			tp, err := key.Thumbprint(crypto.SHA256)
+			require.NoError(t, err)
+			assert.Equal(t, base64.RawURLEncoding.EncodeToString(tp), key.ID(), "kid should default to the thumbprint")
+
+			// The key survives a serialization round trip
+			b, err := key.MarshalJSON()
+			require.NoError(t, err)
+			parsed, err := jwk.ParseString(string(b))
+			require.NoError(t, err)
+			assert.IsType(t, tc.expected, parsed)
+
+			parsedTP, err := parsed.Thumbprint(crypto.SHA256)
+			require.NoError(t, err)
+			assert.Equal(t, tp, parsedTP)
+		})
+	}
+
+	t.Run("explicit claims", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := jwk.FromCrypto(ecKey,
+			jwk.WithKeyID("my-key"),
+			jwk.WithAlgorithm(jwa.KeyAlgorithmMustFrom(jwa.ES384)),
+			jwk.WithUsage(jwk.Signing),
+			jwk.WithThumbprintKeyID(crypto.SHA256),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "my-key", key.ID(), "explicit kid takes precedence over the thumbprint")
+		assert.Equal(t, jwa.KeyAlgorithmMustFrom(jwa.ES384), key.Algorithm())
+		assert.Equal(t, jwk.Signing, key.Usage())
+	})
+
+	t.Run("symmetric key too short", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := jwk.FromCrypto([]byte("short"), jwk.WithAlgorithm(jwa.KeyAlgorithmMustFrom(jwa.HS256)))
+		assert.ErrorIs(t, err, jwk.ErrMalformedKey)
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := jwk.FromCrypto("not a key")
+		assert.ErrorIs(t, err, jwk.ErrUnknownType)
+	})
+
+	t.Run("nil keys", func(t *testing.T) {
+		t.Parallel()
+
+		for _, key := range []any{
+			(*rsa.PrivateKey)(nil),
+			(*rsa.PublicKey)(nil),
+			(*ecdsa.PrivateKey)(nil),
+			(*ecdsa.PublicKey)(nil),
+			(*ecdh.PrivateKey)(nil),
+			(*ecdh.PublicKey)(nil),
+			&rsa.PublicKey{E: 65537},
+			&rsa.PrivateKey{PublicKey: rsaKey.PublicKey, Primes: rsaKey.Primes},
+			&rsa.PrivateKey{PublicKey: rsaKey.PublicKey, D: rsaKey.D, Primes: []*big.Int{rsaKey.Primes[0], nil}},
+			&ecdsa.PublicKey{X: ecKey.X, Y: ecKey.Y},
+			&ecdsa.PrivateKey{PublicKey: ecKey.PublicKey},
+		} {
+			_, err := jwk.FromCrypto(key)
+			assert.ErrorIs(t, err, jwk.ErrMalformedKey, "%T", key)
+		}
+	})
+
+	t.Run("keys are copied", func(t *testing.T) {
+		t.Parallel()
+
+		rsaCopy, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		ecCopy, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		var keys []jwk.Key
+		var thumbprints [][]byte
+		for _, k := range []any{rsaCopy, &rsaCopy.PublicKey, ecCopy, &ecCopy.PublicKey} {
+			key, err := jwk.FromCrypto(k)
+			require.NoError(t, err)
+			tp, err := key.Thumbprint(crypto.SHA256)
+			require.NoError(t, err)
+			keys = append(keys, key)
+			thumbprints = append(thumbprints, tp)
+		}
+
+		rsaCopy.N.SetInt64(1)
+		rsaCopy.D.SetInt64(1)
+		ecCopy.X.SetInt64(1)
+		ecCopy.D.SetInt64(1)
+
+		for i, key := range keys {
+			tp, err := key.Thumbprint(crypto.SHA256)
+			require.NoError(t, err)
+			assert.Equal(t, thumbprints[i], tp)
+
+			b, err := key.MarshalJSON()
+			require.NoError(t, err)
+			_, err = jwk.ParseString(string(b))
+			assert.NoError(t, err, "%T", key)
+		}
+	})
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name   string
+		key    crypto.Signer
+		opts   crypto.SignerOpts
+		verify func(t *testing.T, pub crypto.PublicKey, digest, sig []byte)
+	}{
+		{
+			name: "RSA",
+			key:  rsaKey,
+			opts: crypto.SHA256,
+			verify: func(t *testing.T, pub crypto.PublicKey, digest, sig []byte) {
+				t.Helper()
+				//nolint:forcetypeassert
+				assert.NoError(t, rsa.VerifyPKCS1v15(pub.(*rsa.PublicKey), crypto.SHA256, digest, sig))
+			},
+		},
+		{
+			name: "EC",
+			key:  ecKey,
+			opts: crypto.SHA256,
+			verify: func(t *testing.T, pub crypto.PublicKey, digest, sig []byte) {
+				t.Helper()
+				//nolint:forcetypeassert
+				assert.True(t, ecdsa.VerifyASN1(pub.(*ecdsa.PublicKey), digest, sig))
+			},
+		},
+		{
+			name: "Ed25519",
+			key:  edKey,
+			opts: crypto.Hash(0),
+			verify: func(t *testing.T, pub crypto.PublicKey, msg, sig []byte) {
+				t.Helper()
+				//nolint:forcetypeassert
+				assert.True(t, ed25519.Verify(pub.(ed25519.PublicKey), msg, sig))
+			},
+		},
+	} {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			key, err := jwk.FromCrypto(tc.key)
+			require.NoError(t, err)
+			assert.Equal(t, tc.key, key.Raw(), "Raw should return the original key")
+
+			signer, ok := key.(crypto.Signer)
+			require.True(t, ok, "private keys implement crypto.Signer")
+
+			digest := []byte("01234567890123456789012345678901")
+			sig, err := signer.Sign(rand.Reader, digest, tc.opts)
+			require.NoError(t, err)
+
+			pub, err := jwk.PublicKeyOf(key)
+			require.NoError(t, err)
+			assert.Equal(t, tc.key.Public(), pub)
+			tc.verify(t, pub, digest, sig)
+
+			pubKey, err := key.PublicKey()
+			require.NoError(t, err)
+			assert.Equal(t, tc.key.Public(), pubKey.Raw())
+
+			b, err := pubKey.MarshalJSON()
+			require.NoError(t, err)
+			assert.NotContains(t, string(b), `"d":`, "public key should not contain private claims")
+		})
+	}
+
+	t.Run("RSA Decrypt", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := jwk.FromCrypto(rsaKey)
+		require.NoError(t, err)
+
+		ct, err := rsa.EncryptOAEP(crypto.SHA256.New(), rand.Reader, &rsaKey.PublicKey, []byte("secret"), nil)
+		require.NoError(t, err)
+
+		//nolint:forcetypeassert
+		pt, err := key.(crypto.Decrypter).Decrypt(rand.Reader, ct, &rsa.OAEPOptions{Hash: crypto.SHA256})
+		require.NoError(t, err)
+		assert.Equal(t, []byte("secret"), pt)
+	})
+
+	t.Run("symmetric keys have no public key", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := jwk.FromCrypto([]byte("0123456789abcdef0123456789abcdef"))
+		require.NoError(t, err)
+
+		_, err = key.PublicKey()
+		assert.ErrorIs(t, err, jwk.ErrNoPublicKey)
+		_, err = jwk.PublicKeyOf(key)
+		assert.ErrorIs(t, err, jwk.ErrNoPublicKey)
+	})
+}
+
+func TestKeyOperationEnforcement(t *testing.T) {
+	t.Parallel()
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name    string
+		opts    []jwk.KeyOption
+		allowed bool
+	}{
+		{name: "no restrictions", allowed: true},
+		{name: "use sig", opts: []jwk.KeyOption{jwk.WithUsage(jwk.Signing)}, allowed: true},
+		{name: "use enc", opts: []jwk.KeyOption{jwk.WithUsage(jwk.Encryption)}, allowed: false},
+		{name: "key_ops sign", opts: []jwk.KeyOption{jwk.WithKeyOps(jwk.KeyOpSign)}, allowed: true},
+		{name: "key_ops verify", opts: []jwk.KeyOption{jwk.WithKeyOps(jwk.KeyOpVerify)}, allowed: false},
+	} {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			key, err := jwk.FromCrypto(ecKey, tc.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, tc.allowed, key.AllowsOperation(jwk.KeyOpSign))
+
+			//nolint:forcetypeassert
+			_, err = key.(crypto.Signer).Sign(rand.Reader, make([]byte, 32), crypto.SHA256)
+			if tc.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, jwk.ErrOperationNotPermitted)
+			}
+		})
+	}
+
+	t.Run("public key operations", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := jwk.FromCrypto(ecKey, jwk.WithKeyOps(jwk.KeyOpSign, jwk.KeyOpVerify))
+		require.NoError(t, err)
+
+		pub, err := key.PublicKey()
+		require.NoError(t, err)
+		assert.Equal(t, []jwk.KeyOperation{jwk.KeyOpVerify}, pub.KeyOps())
+		assert.True(t, pub.AllowsOperation(jwk.KeyOpVerify))
+	})
+
+	t.Run("conflicting options", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := jwk.FromCrypto(ecKey, jwk.WithUsage(jwk.Encryption), jwk.WithKeyOps(jwk.KeyOpSign))
+		assert.ErrorIs(t, err, jwk.ErrMalformedKey)
+	})
+}
@@ -0,0 +1,282 @@
+package jwk
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/jgraeger/jwgo/internal/base64"
+	"github.com/jgraeger/jwgo/internal/pool"
+)
+
+// maxCoordinateSize is the size of the biggest coordinate of the supported curves (P-521).
+const maxCoordinateSize = 66
+
+type ECPrivateKey struct {
+	Header
+	ecdsa *ecdsa.PrivateKey
+}
+
+func (k ECPrivateKey) Thumbprint(hash crypto.Hash) ([]byte, error) {
+	return ecThumbPrint(hash, &k.ecdsa.PublicKey)
+}
+
+// Curve returns the curve of the key (`crv` claim).
+func (k ECPrivateKey) Curve() Curve {
+	return Curve(k.ecdsa.Curve.Params().Name)
+}
+
+// Raw returns the underlying *ecdsa.PrivateKey.
+func (k ECPrivateKey) Raw() any {
+	return k.ecdsa
+}
+
+func (k ECPrivateKey) PublicKey() (Key, error) {
+	return &ECPublicKey{
+		Header: k.Header.public(),
+		ecdsa:  &k.ecdsa.PublicKey,
+	}, nil
+}
+
+// Public implements crypto.Signer.
+func (k ECPrivateKey) Public() crypto.PublicKey {
+	return &k.ecdsa.PublicKey
+}
+
+// Sign implements crypto.Signer. The signature is ASN.1 encoded.
+func (k ECPrivateKey) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if !k.AllowsOperation(KeyOpSign) {
+		return nil, operationNotPermittedErr(KeyOpSign)
+	}
+	return k.ecdsa.Sign(rand, digest, opts)
+}
+
+func (k ECPrivateKey) MarshalJSON() ([]byte, error) {
+	crv := k.Curve()
+	size := crv.coordinateSize()
+	if size == 0 {
+		return nil, unknownCurveErr(string(crv))
+	}
+
+	return marshalKey(k.Header, EC, func(buf *bytes.Buffer) {
+		var coord [maxCoordinateSize]byte
+		writeECPublicClaims(buf, &k.ecdsa.PublicKey, crv, size)
+		writeBase64Member(buf, "d", k.ecdsa.D.FillBytes(coord[:size]))
+	}), nil
+}
+
+type ECPublicKey struct {
+	Header
+	ecdsa *ecdsa.PublicKey
+}
+
+func (k ECPublicKey) Thumbprint(hash crypto.Hash) ([]byte, error) {
+	return ecThumbPrint(hash, k.ecdsa)
+}
+
+// Curve returns the curve of the key (`crv` claim).
+func (k ECPublicKey) Curve() Curve {
+	return Curve(k.ecdsa.Curve.Params().Name)
+}
+
+// Raw returns the underlying *ecdsa.PublicKey.
+func (k ECPublicKey) Raw() any {
+	return k.ecdsa
+}
+
+func (k ECPublicKey) PublicKey() (Key, error) {
+	return &k, nil
+}
+
+// Public returns the underlying *ecdsa.PublicKey.
+func (k ECPublicKey) Public() crypto.PublicKey {
+	return k.ecdsa
+}
+
+func (k ECPublicKey) MarshalJSON() ([]byte, error) {
+	crv := k.Curve()
+	size := crv.coordinateSize()
+	if size == 0 {
+		return nil, unknownCurveErr(string(crv))
+	}
+
+	return marshalKey(k.Header, EC, func(buf *bytes.Buffer) {
+		writeECPublicClaims(buf, k.ecdsa, crv, size)
+	}), nil
+}
+
+// Interface guards
+var (
+	_ Key           = (*ECPrivateKey)(nil)
+	_ Key           = (*ECPublicKey)(nil)
+	_ crypto.Signer = (*ECPrivateKey)(nil)
+)
+
+func (c Curve) ellipticCurve() (elliptic.Curve, bool) {
+	switch c {
+	case P256:
+		return elliptic.P256(), true
+	case P384:
+		return elliptic.P384(), true
+	case P521:
+		return elliptic.P521(), true
+	default:
+		return nil, false
+	}
+}
+
+func (c Curve) ecdhCurve() (ecdh.Curve, bool) {
+	switch c {
+	case P256:
+		return ecdh.P256(), true
+	case P384:
+		return ecdh.P384(), true
+	case P521:
+		return ecdh.P521(), true
+	case X25519:
+		return ecdh.X25519(), true
+	default:
+		return nil, false
+	}
+}
+
+// coordinateSize returns the size of a coordinate on the curve in bytes.
+func (c Curve) coordinateSize() int {
+	switch c {
+	case P256, Ed25519, X25519:
+		return 32
+	case P384:
+		return 48
+	case P521:
+		return maxCoordinateSize
+	default:
+		return 0
+	}
+}
+
+func ecThumbPrint(hash crypto.Hash, key *ecdsa.PublicKey) ([]byte, error) {
+	crv := Curve(key.Curve.Params().Name)
+	size := crv.coordinateSize()
+	if size == 0 {
+		return nil, unknownCurveErr(string(crv))
+	}
+
+	buf := pool.GetBytesBuffer()
+	defer pool.PutBytesBuffer(buf)
+
+	// Coordinates must be encoded using the full size of the curve
+	var coord [maxCoordinateSize]byte
+
+	buf.WriteString(`{"crv":"`)
+	buf.WriteString(string(crv))
+	buf.WriteString(`","kty":"EC","x":"`)
+	buf.WriteString(base64.RawURLEncoding.EncodeToString(key.X.FillBytes(coord[:size])))
+	buf.WriteString(`","y":"`)
+	buf.WriteString(base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(coord[:size])))
+	buf.WriteString(`"}`)
+
+	h := hash.New()
+	if _, err := buf.WriteTo(h); err != nil {
+		return nil, fmt.Errorf("write thumbprint to hash: %w", err)
+	}
+	return h.Sum(nil), nil
+}
+
+func writeECPublicClaims(buf *bytes.Buffer, key *ecdsa.PublicKey, crv Curve, size int) {
+	var coord [maxCoordinateSize]byte
+
+	writeStringMember(buf, "crv", string(crv))
+	writeBase64Member(buf, "x", key.X.FillBytes(coord[:size]))
+	writeBase64Member(buf, "y", key.Y.FillBytes(coord[:size]))
+}
+
+func (pk parsedJWK) toECKey(cfg parseConfig) (Key, error) {
+	crv := Curve(pk.k["crv"])
+	curve, ok := crv.ellipticCurve()
+	if !ok {
+		return nil, unknownCurveErr(string(crv))
+	}
+	ecdhCurve, _ := crv.ecdhCurve()
+	size := crv.coordinateSize()
+
+	x, err := getFixedBytesClaim(pk.k, "x", size)
+	if err != nil {
+		return nil, err
+	}
+	y, err := getFixedBytesClaim(pk.k, "y", size)
+	if err != nil {
+		return nil, err
+	}
+
+	// crypto/ecdh rejects points that are not on the curve, so we pass it the
+	// uncompressed encoding of the point.
+	point := make([]byte, 1+2*size)
+	point[0] = 4
+	copy(point[1:], x)
+	copy(point[1+size:], y)
+	if !cfg.skipValidation {
+		if _, err := ecdhCurve.NewPublicKey(point); err != nil {
+			return nil, fmt.Errorf("%w: ec: point is not on curve %s", ErrMalformedKey, crv)
+		}
+	}
+
+	pub := ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}
+
+	if _, ok := pk.k["d"]; !ok {
+		return &ECPublicKey{
+			Header: pk.Header,
+			ecdsa:  &pub,
+		}, nil
+	}
+
+	d, err := getFixedBytesClaim(pk.k, "d", size)
+	if err != nil {
+		return nil, err
+	}
+
+	if !cfg.skipValidation {
+		priv, err := ecdhCurve.NewPrivateKey(d)
+		if err != nil {
+			return nil, fmt.Errorf("%w: ec: invalid private key: %w", ErrMalformedKey, err)
+		} else if !bytes.Equal(priv.PublicKey().Bytes(), point) {
+			return nil, fmt.Errorf("%w: ec: private key does not match public key", ErrMalformedKey)
+		}
+	}
+
+	return &ECPrivateKey{
+		Header: pk.Header,
+		ecdsa: &ecdsa.PrivateKey{
+			PublicKey: pub,
+			D:         new(big.Int).SetBytes(d),
+		},
+	}, nil
+}
+
+// getFixedBytesClaim decodes a claim into a buffer of exactly size bytes.
+// Some encoders strip leading zeros, so shorter values are padded.
+func getFixedBytesClaim(claims parsedClaims, c string, size int) ([]byte, error) {
+	b, err := getBytesClaim(claims, c)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(b) == size:
+		return b, nil
+	case len(b) > size:
+		return nil, fmt.Errorf("%w: claim `%s` exceeds %d bytes", ErrMalformedKey, c, size)
+	default:
+		padded := make([]byte, size)
+		copy(padded[size-len(b):], b)
+		return padded, nil
+	}
+}
@@ -1,6 +1,7 @@
 package jwk
 
 import (
+	"crypto/x509"
 	"errors"
 	"fmt"
 )
@@ -10,9 +11,43 @@ var (
 
 	ErrUnknownType   = errors.New("unknown key type")
 	ErrUnknownUse    = errors.New("unknown key usage")
+	ErrUnknownCurve  = errors.New("unknown curve")
+	ErrNoPublicKey   = errors.New("key has no public key")
 	ErrMalformedJSON = errors.New("malformed JSON")
+
+	ErrDuplicateMember = errors.New("duplicate member")
+	ErrUnknownMember   = errors.New("unknown member")
+	ErrInputTooLarge   = errors.New("input too large")
+
+	ErrKeyNotFound = errors.New("key not found")
+
+	ErrUnknownKeyOperation   = errors.New("unknown key operation")
+	ErrOperationNotPermitted = errors.New("key operation not permitted")
+
+	ErrCertificateMismatch = errors.New("certificate does not match key")
+	ErrInvalidCertChain    = errors.New("invalid certificate chain")
 )
 
+// CertificateError is returned when the verification of a certificate chain fails.
+type CertificateError struct {
+	// Index of the certificate in the `x5c` claim, or -1 if it is not part of it,
+	// e.g. a root certificate from the pool.
+	Index       int
+	Certificate *x509.Certificate
+	Reason      error
+}
+
+func (e *CertificateError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("%s: certificate %q: %s", ErrInvalidCertChain, e.Certificate.Subject, e.Reason)
+	}
+	return fmt.Sprintf("%s: %s[%d] %q: %s", ErrInvalidCertChain, ClaimX5c, e.Index, e.Certificate.Subject, e.Reason)
+}
+
+func (e *CertificateError) Unwrap() []error {
+	return []error{ErrInvalidCertChain, e.Reason}
+}
+
 func unknownKeyTypeErr(kty string) error {
 	return fmt.Errorf("%w: %s", ErrUnknownType, kty)
 }
@@ -20,3 +55,19 @@ func unknownKeyTypeErr(kty string) error {
 func unknownKeyUseErr(use string) error {
 	return fmt.Errorf("%w: %s", ErrUnknownUse, use)
 }
+
+func unknownCurveErr(crv string) error {
+	return fmt.Errorf("%w: %s", ErrUnknownCurve, crv)
+}
+
+func unknownKeyOperationErr(op string) error {
+	return fmt.Errorf("%w: %s", ErrUnknownKeyOperation, op)
+}
+
+func keyNotFoundErr(kid string) error {
+	return fmt.Errorf("%w: %s", ErrKeyNotFound, kid)
+}
+
+func operationNotPermittedErr(op KeyOperation) error {
+	return fmt.Errorf("%w: %s", ErrOperationNotPermitted, op)
+}
@@ -0,0 +1,70 @@
+package jwk
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/goccy/go-json"
+	"github.com/jgraeger/jwgo/internal/jsonenc"
+)
+
+// Extensions holds JWK claims that are not interpreted by this package.
+// Values are decoded as string, float64, bool, nil, []any or map[string]any.
+type Extensions map[string]any
+
+// Get returns the value of the claim.
+func (e Extensions) Get(name string) (any, bool) {
+	v, ok := e[name]
+	return v, ok
+}
+
+// String returns the value of the claim, if it is a string.
+func (e Extensions) String(name string) (string, bool) {
+	s, ok := e[name].(string)
+	return s, ok
+}
+
+// Strings returns the value of the claim, if it is an array of strings.
+func (e Extensions) Strings(name string) ([]string, bool) {
+	arr, ok := e[name].([]any)
+	if !ok {
+		return nil, false
+	}
+
+	strs := make([]string, len(arr))
+	for i, v := range arr {
+		s, ok := v.(string)
+		if !ok {
+			return nil, false
+		}
+		strs[i] = s
+	}
+	return strs, true
+}
+
+// writeJSON writes the claims as members of an open JSON object into buf.
+// They are sorted by name, so the output is deterministic.
+// Values that cannot be encoded are skipped.
+func (e Extensions) writeJSON(buf *bytes.Buffer) {
+	if len(e) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(e))
+	for name := range e {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		b, err := json.Marshal(e[name])
+		if err != nil {
+			continue
+		}
+
+		buf.WriteByte(',')
+		jsonenc.WriteString(buf, name)
+		buf.WriteByte(':')
+		buf.Write(b)
+	}
+}
@@ -0,0 +1,133 @@
+package jwk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultMaxSetSize limits the size of fetched key sets, unless set by WithMaxInputSize.
+const defaultMaxSetSize = 1 << 20
+
+// Fetcher loads key sets from HTTPS URLs.
+type Fetcher struct {
+	client *http.Client
+	opts   []ParseOption
+}
+
+// NewFetcher creates a fetcher that uses the client for requests
+// and the options to parse the key sets. The size of key sets is limited to 1 MiB,
+// unless set by WithMaxInputSize.
+func NewFetcher(client *http.Client, opts ...ParseOption) *Fetcher {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Fetcher{
+		client: client,
+		opts:   append([]ParseOption{WithMaxInputSize(defaultMaxSetSize)}, opts...),
+	}
+}
+
+// Fetch loads the key set from the URL.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*Set, error) {
+	res, err := f.fetch(ctx, rawURL, "")
+	if err != nil {
+		return nil, err
+	}
+	return res.set, nil
+}
+
+// fetchResult holds a fetched key set and its caching information.
+type fetchResult struct {
+	// set is nil if the key set was not modified
+	set  *Set
+	etag string
+	// lifetime of the key set, if the response has caching headers
+	lifetime    time.Duration
+	hasLifetime bool
+}
+
+// fetch loads the key set from the URL. If etag is set, the request is conditional.
+func (f *Fetcher) fetch(ctx context.Context, rawURL, etag string) (fetchResult, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fetchResult{}, fmt.Errorf("fetch key set: %w", err)
+	} else if u.Scheme != "https" {
+		return fetchResult{}, fmt.Errorf("fetch key set: URL %s must use https", rawURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return fetchResult{}, fmt.Errorf("fetch key set: %w", err)
+	}
+	req.Header.Set("Accept", "application/jwk-set+json, application/json")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fetchResult{}, fmt.Errorf("fetch key set: %w", err)
+	}
+	defer resp.Body.Close()
+
+	res := fetchResult{etag: etag}
+	res.lifetime, res.hasLifetime = cacheLifetime(resp.Header)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		if etag != "" {
+			return res, nil
+		}
+		fallthrough
+	default:
+		return fetchResult{}, fmt.Errorf("fetch key set %s: unexpected status %s", rawURL, resp.Status)
+	}
+
+	res.set, err = ParseSetReader(resp.Body, f.opts...)
+	if err != nil {
+		return fetchResult{}, fmt.Errorf("fetch key set %s: %w", rawURL, err)
+	}
+	res.etag = resp.Header.Get("ETag")
+	return res, nil
+}
+
+// cacheLifetime returns how long a response may be cached according to
+// the Cache-Control and Expires headers.
+func cacheLifetime(h http.Header) (time.Duration, bool) {
+	if cc := h.Get("Cache-Control"); cc != "" {
+		for _, directive := range strings.Split(cc, ",") {
+			directive = strings.ToLower(strings.TrimSpace(directive))
+			switch {
+			case directive == "no-store" || directive == "no-cache":
+				return 0, true
+			case strings.HasPrefix(directive, "max-age="):
+				secs, err := strconv.ParseInt(strings.TrimPrefix(directive, "max-age="), 10, 64)
+				if err == nil && secs >= 0 {
+					return time.Duration(secs) * time.Second, true
+				}
+			}
+		}
+	}
+
+	if exp := h.Get("Expires"); exp != "" {
+		t, err := http.ParseTime(exp)
+		if err != nil {
+			// Invalid dates represent a time in the past (RFC 9111, Section 5.3)
+			return 0, true
+		}
+
+		now := time.Now()
+		if date, err := http.ParseTime(h.Get("Date")); err == nil {
+			now = date
+		}
+		return max(t.Sub(now), 0), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,170 @@
+package jwk
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"strings"
+
+	"github.com/jgraeger/jwgo/jwa"
+)
+
+const (
+	// DefaultRSABits is the size of generated RSA keys, unless set by WithRSABits.
+	DefaultRSABits = 2048
+	// MinRSABits is the minimum size of generated RSA keys.
+	MinRSABits = 2048
+)
+
+// Generate creates a new private key of the key type.
+//
+// The size of RSA keys is set by WithRSABits and the curve of EC and OKP keys by WithCurve,
+// otherwise it is derived from the algorithm. Symmetric keys have the minimum size
+// required by the algorithm. Unless set by options, the `alg` and `use` claims are set
+// to the default of the key type and the `kid` claim to the SHA-256 thumbprint of the key.
+func Generate(kty KeyType, opts ...KeyOption) (Key, error) {
+	cfg := newKeyConfig(opts)
+
+	crv := cfg.crv
+	if crv == "" {
+		crv = defaultCurve(kty, cfg.alg)
+	}
+
+	if cfg.alg == "" {
+		cfg.alg = defaultAlgorithm(kty, crv)
+	} else if !algorithmFits(kty, crv, cfg.alg) {
+		return nil, fmt.Errorf("%w: algorithm %s cannot be used with %s keys", ErrMalformedKey, cfg.alg, kty)
+	}
+
+	if cfg.use == Unspecified && len(cfg.ops) == 0 {
+		cfg.use = Signing
+		if crv == X25519 {
+			cfg.use = Encryption
+		}
+	}
+	if cfg.kid == "" && cfg.thumbprintHash == 0 {
+		cfg.thumbprintHash = crypto.SHA256
+	}
+
+	raw, err := generateRaw(kty, crv, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := fromCrypto(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.apply(k); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func generateRaw(kty KeyType, crv Curve, cfg keyConfig) (any, error) {
+	switch kty {
+	case RSA:
+		bits := cfg.rsaBits
+		if bits == 0 {
+			bits = DefaultRSABits
+		} else if bits < MinRSABits {
+			return nil, fmt.Errorf("%w: rsa: key size of %d bits is below the minimum of %d bits", ErrMalformedKey, bits, MinRSABits)
+		}
+		return rsa.GenerateKey(rand.Reader, bits)
+	case EC:
+		curve, ok := crv.ellipticCurve()
+		if !ok {
+			return nil, unknownCurveErr(string(crv))
+		}
+		return ecdsa.GenerateKey(curve, rand.Reader)
+	case OKP:
+		switch crv {
+		case Ed25519:
+			_, priv, err := ed25519.GenerateKey(rand.Reader)
+			return priv, err
+		case X25519:
+			return ecdh.X25519().GenerateKey(rand.Reader)
+		default:
+			return nil, unknownCurveErr(string(crv))
+		}
+	case Oct:
+		k := make([]byte, max(minSymmetricKeySize(cfg.alg), 32))
+		if _, err := rand.Read(k); err != nil {
+			return nil, err
+		}
+		return k, nil
+	default:
+		return nil, unknownKeyTypeErr(string(kty))
+	}
+}
+
+// defaultCurve returns the curve of new keys of the key type, which is
+// derived from the algorithm if possible.
+func defaultCurve(kty KeyType, alg jwa.KeyAlgorithm) Curve {
+	switch kty {
+	case EC:
+		switch alg {
+		case jwa.KeyAlgorithm(jwa.ES384):
+			return P384
+		case jwa.KeyAlgorithm(jwa.ES512):
+			return P521
+		default:
+			return P256
+		}
+	case OKP:
+		return Ed25519
+	default:
+		return ""
+	}
+}
+
+// defaultAlgorithm returns the algorithm of new keys of the key type and curve.
+// X25519 keys are only used for key agreement, so they have no default.
+func defaultAlgorithm(kty KeyType, crv Curve) jwa.KeyAlgorithm {
+	switch {
+	case kty == RSA:
+		return jwa.KeyAlgorithm(jwa.RS256)
+	case kty == EC && crv == P256:
+		return jwa.KeyAlgorithm(jwa.ES256)
+	case kty == EC && crv == P384:
+		return jwa.KeyAlgorithm(jwa.ES384)
+	case kty == EC && crv == P521:
+		return jwa.KeyAlgorithm(jwa.ES512)
+	case kty == OKP && crv == Ed25519:
+		return jwa.KeyAlgorithm(jwa.EdDSA)
+	case kty == Oct:
+		return jwa.KeyAlgorithm(jwa.HS256)
+	default:
+		return ""
+	}
+}
+
+// inferAlgorithm returns the default algorithm of the key.
+func inferAlgorithm(k Key) jwa.KeyAlgorithm {
+	var crv Curve
+	switch ck := k.(type) {
+	case interface{ Curve() Curve }:
+		crv = ck.Curve()
+	case *SymmetricKey:
+		if len(ck.k) < minSymmetricKeySize(jwa.KeyAlgorithm(jwa.HS256)) {
+			return ""
+		}
+	}
+	return defaultAlgorithm(k.Type(), crv)
+}
+
+// algorithmFits reports whether the algorithm can be used with keys of the key type and curve.
+func algorithmFits(kty KeyType, crv Curve, alg jwa.KeyAlgorithm) bool {
+	switch kty {
+	case RSA:
+		return strings.HasPrefix(alg.String(), "RS") || strings.HasPrefix(alg.String(), "PS")
+	case Oct:
+		return strings.HasPrefix(alg.String(), "HS")
+	default:
+		return alg == defaultAlgorithm(kty, crv)
+	}
+}
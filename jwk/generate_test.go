@@ -0,0 +1,168 @@
+package jwk_test
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/jgraeger/jwgo/internal/base64"
+	"github.com/jgraeger/jwgo/jwa"
+	"github.com/jgraeger/jwgo/jwk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name        string
+		kty         jwk.KeyType
+		opts        []jwk.KeyOption
+		expectedAlg jwa.SignatureAlgorithm
+		expectedUse jwk.KeyUsage
+		assertions  func(*testing.T, jwk.Key)
+		expectedErr error
+	}{
+		{
+			name:        "RSA default",
+			kty:         jwk.RSA,
+			expectedAlg: jwa.RS256,
+			expectedUse: jwk.Signing,
+			assertions: func(t *testing.T, k jwk.Key) {
+				assert.Equal(t, 2048, k.Raw().(*rsa.PrivateKey).N.BitLen())
+			},
+		},
+		{
+			name:        "RSA with bits and algorithm",
+			kty:         jwk.RSA,
+			opts:        []jwk.KeyOption{jwk.WithRSABits(3072), jwk.WithAlgorithm(jwa.KeyAlgorithmMustFrom(jwa.PS256))},
+			expectedAlg: jwa.PS256,
+			expectedUse: jwk.Signing,
+			assertions: func(t *testing.T, k jwk.Key) {
+				assert.Equal(t, 3072, k.Raw().(*rsa.PrivateKey).N.BitLen())
+			},
+		},
+		{
+			name:        "RSA below minimum size",
+			kty:         jwk.RSA,
+			opts:        []jwk.KeyOption{jwk.WithRSABits(1024)},
+			expectedErr: jwk.ErrMalformedKey,
+		},
+		{
+			name:        "EC default",
+			kty:         jwk.EC,
+			expectedAlg: jwa.ES256,
+			expectedUse: jwk.Signing,
+			assertions: func(t *testing.T, k jwk.Key) {
+				assert.Equal(t, jwk.P256, k.(*jwk.ECPrivateKey).Curve())
+			},
+		},
+		{
+			name:        "EC curve from algorithm",
+			kty:         jwk.EC,
+			opts:        []jwk.KeyOption{jwk.WithAlgorithm(jwa.KeyAlgorithmMustFrom(jwa.ES512))},
+			expectedAlg: jwa.ES512,
+			expectedUse: jwk.Signing,
+			assertions: func(t *testing.T, k jwk.Key) {
+				assert.Equal(t, jwk.P521, k.(*jwk.ECPrivateKey).Curve())
+				assert.IsType(t, &ecdsa.PrivateKey{}, k.Raw())
+			},
+		},
+		{
+			name:        "EC algorithm from curve",
+			kty:         jwk.EC,
+			opts:        []jwk.KeyOption{jwk.WithCurve(jwk.P384)},
+			expectedAlg: jwa.ES384,
+			expectedUse: jwk.Signing,
+		},
+		{
+			name:        "EC curve not matching algorithm",
+			kty:         jwk.EC,
+			opts:        []jwk.KeyOption{jwk.WithCurve(jwk.P384), jwk.WithAlgorithm(jwa.KeyAlgorithmMustFrom(jwa.ES256))},
+			expectedErr: jwk.ErrMalformedKey,
+		},
+		{
+			name:        "EC unknown curve",
+			kty:         jwk.EC,
+			opts:        []jwk.KeyOption{jwk.WithCurve(jwk.Ed25519)},
+			expectedErr: jwk.ErrUnknownCurve,
+		},
+		{
+			name:        "OKP default",
+			kty:         jwk.OKP,
+			expectedAlg: jwa.EdDSA,
+			expectedUse: jwk.Signing,
+			assertions: func(t *testing.T, k jwk.Key) {
+				assert.IsType(t, ed25519.PrivateKey{}, k.Raw())
+			},
+		},
+		{
+			name:        "OKP X25519",
+			kty:         jwk.OKP,
+			opts:        []jwk.KeyOption{jwk.WithCurve(jwk.X25519)},
+			expectedUse: jwk.Encryption,
+			assertions: func(t *testing.T, k jwk.Key) {
+				assert.IsType(t, &ecdh.PrivateKey{}, k.Raw())
+			},
+		},
+		{
+			name:        "oct default",
+			kty:         jwk.Oct,
+			expectedAlg: jwa.HS256,
+			expectedUse: jwk.Signing,
+			assertions: func(t *testing.T, k jwk.Key) {
+				assert.Len(t, k.Raw(), 32)
+			},
+		},
+		{
+			name:        "oct size from algorithm",
+			kty:         jwk.Oct,
+			opts:        []jwk.KeyOption{jwk.WithAlgorithm(jwa.KeyAlgorithmMustFrom(jwa.HS512)), jwk.WithKeyID("hmac")},
+			expectedAlg: jwa.HS512,
+			expectedUse: jwk.Signing,
+			assertions: func(t *testing.T, k jwk.Key) {
+				assert.Len(t, k.Raw(), 64)
+				assert.Equal(t, "hmac", k.ID())
+			},
+		},
+		{
+			name:        "oct with RSA algorithm",
+			kty:         jwk.Oct,
+			opts:        []jwk.KeyOption{jwk.WithAlgorithm(jwa.KeyAlgorithmMustFrom(jwa.RS256))},
+			expectedErr: jwk.ErrMalformedKey,
+		},
+		{
+			name:        "unknown key type",
+			kty:         "DSA",
+			expectedErr: jwk.ErrUnknownType,
+		},
+	} {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			k, err := jwk.Generate(tc.kty, tc.opts...)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.kty, k.Type())
+			assert.Equal(t, tc.expectedAlg.String(), k.Algorithm().String())
+			assert.Equal(t, tc.expectedUse, k.Usage())
+			if k.ID() != "hmac" {
+				tp, err := k.Thumbprint(crypto.SHA256)
+				require.NoError(t, err)
+				assert.Equal(t, base64.RawURLEncoding.EncodeToString(tp), k.ID())
+			}
+			if tc.assertions != nil {
+				tc.assertions(t, k)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package jwk
+
+import (
+	"crypto/sha256"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jgraeger/jwgo/internal/base64"
+)
+
+// DefaultSetMaxAge is the time clients may cache a served key set, unless set by WithMaxAge.
+const DefaultSetMaxAge = 15 * time.Minute
+
+// ContentTypeJWKSet is the media type of key sets (RFC 7517, Section 8.5.1).
+const ContentTypeJWKSet = "application/jwk-set+json"
+
+// SetHandler is a http.Handler that serves the public keys of a key set.
+// The key set can be replaced at any time using Store.
+type SetHandler struct {
+	maxAge time.Duration
+	set    atomic.Pointer[servedSet]
+}
+
+// servedSet is the encoded key set served by a SetHandler.
+type servedSet struct {
+	body []byte
+	etag string
+}
+
+// NewSetHandler creates a handler that serves the public keys of the set.
+func NewSetHandler(set *Set, opts ...HandlerOption) (*SetHandler, error) {
+	h := &SetHandler{maxAge: newHandlerConfig(opts).maxAge}
+	if err := h.Store(set); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Store atomically replaces the served key set. Private key material is removed,
+// and symmetric keys are left out.
+func (h *SetHandler) Store(set *Set) error {
+	if set == nil {
+		return errors.New("nil key set")
+	}
+
+	pub, err := set.PublicKeys()
+	if err != nil {
+		return err
+	}
+
+	body, err := pub.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(body)
+	h.set.Store(&servedSet{
+		body: body,
+		etag: `"` + base64.RawURLEncoding.EncodeToString(sum[:]) + `"`,
+	})
+	return nil
+}
+
+func (h *SetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	s := h.set.Load()
+
+	header := w.Header()
+	header.Set("ETag", s.etag)
+	header.Set("Cache-Control", "public, max-age="+strconv.Itoa(int(h.maxAge.Seconds())))
+
+	if etagMatches(r.Header.Get("If-None-Match"), s.etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	header.Set("Content-Type", ContentTypeJWKSet)
+	header.Set("Content-Length", strconv.Itoa(len(s.body)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(s.body)
+	}
+}
+
+// etagMatches reports whether the If-None-Match header contains the ETag.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == etag || tag == "*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package jwk_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jgraeger/jwgo/jwk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetHandler(t *testing.T) {
+	t.Parallel()
+
+	var keys []jwk.Key
+	for _, kty := range []jwk.KeyType{jwk.RSA, jwk.EC, jwk.OKP, jwk.Oct} {
+		k, err := jwk.Generate(kty)
+		require.NoError(t, err)
+		keys = append(keys, k)
+	}
+
+	h, err := jwk.NewSetHandler(jwk.NewSet(keys...))
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, jwk.ContentTypeJWKSet, rec.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=900", rec.Header().Get("Cache-Control"))
+	assert.NotContains(t, rec.Body.String(), `"d":`)
+	assert.NotContains(t, rec.Body.String(), `"k":`)
+
+	set, err := jwk.ParseSet(rec.Body.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, 3, set.Len())
+	for i, k := range set.Keys() {
+		assert.Equal(t, keys[i].ID(), k.ID())
+		assert.Equal(t, keys[i].Algorithm(), k.Algorithm())
+		pub, err := keys[i].PublicKey()
+		require.NoError(t, err)
+		assert.IsType(t, pub, k)
+	}
+
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	t.Run("not modified", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("If-None-Match", `"other", `+etag)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	})
+
+	t.Run("head", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{}")))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+		assert.Equal(t, "GET, HEAD", rec.Header().Get("Allow"))
+	})
+}
+
+func TestSetHandlerStore(t *testing.T) {
+	t.Parallel()
+
+	first, err := jwk.Generate(jwk.EC)
+	require.NoError(t, err)
+	second, err := jwk.Generate(jwk.OKP)
+	require.NoError(t, err)
+
+	h, err := jwk.NewSetHandler(jwk.NewSet(first), jwk.WithMaxAge(0))
+	require.NoError(t, err)
+
+	srv := httptest.NewTLSServer(h)
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := jwk.NewCache(ctx, jwk.NewFetcher(srv.Client()), srv.URL, jwk.WithMinRefreshInterval(0))
+	_, err = c.LookupKeyID(ctx, first.ID())
+	require.NoError(t, err)
+
+	require.NoError(t, h.Store(jwk.NewSet(first, second)))
+
+	k, err := c.LookupKeyID(ctx, second.ID())
+	require.NoError(t, err)
+	assert.IsType(t, &jwk.OKPPublicKey{}, k)
+
+	// A nil set is rejected and the served set is kept
+	_, err = jwk.NewSetHandler(nil)
+	assert.Error(t, err)
+	assert.Error(t, h.Store(nil))
+	_, err = c.LookupKeyID(ctx, second.ID())
+	assert.NoError(t, err)
+}
@@ -1,13 +1,32 @@
 package jwk
 
-import "github.com/jgraeger/jwgo/jwa"
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"slices"
+
+	"github.com/jgraeger/jwgo/internal/jsonenc"
+	"github.com/jgraeger/jwgo/jwa"
+)
 
 // Header represents the JWK claims shared by every key type
 type Header struct {
-	Kty KeyType
-	Alg jwa.KeyAlgorithm `json:"alg,omitempty`
-	Kid string           `json:"kid,omitempty`
-	Use KeyUsage         `json:"use,omitempty`
+	Kty KeyType          `json:"kty"`
+	Alg jwa.KeyAlgorithm `json:"alg,omitempty"`
+	Kid string           `json:"kid,omitempty"`
+	Use KeyUsage         `json:"use,omitempty"`
+	Ops []KeyOperation   `json:"key_ops,omitempty"`
+	// X.509 certificate chain, with the leaf certificate first (`x5c` claim)
+	X5C []*x509.Certificate `json:"-"`
+	// SHA-1 thumbprint of the leaf certificate (`x5t` claim)
+	X5T []byte `json:"-"`
+	// SHA-256 thumbprint of the leaf certificate (`x5t#S256` claim)
+	X5TS256 []byte `json:"-"`
+	// URL of the X.509 certificate chain (`x5u` claim)
+	X5U string `json:"x5u,omitempty"`
+	// Ext holds the claims that are not interpreted by this package
+	Ext Extensions `json:"-"`
 }
 
 func (h Header) Type() KeyType {
@@ -25,3 +44,96 @@ func (h Header) ID() string {
 func (h Header) Usage() KeyUsage {
 	return h.Use
 }
+
+func (h Header) KeyOps() []KeyOperation {
+	return h.Ops
+}
+
+// AllowsOperation reports whether `use` and `key_ops` permit the operation.
+// Keys without both claims permit every operation.
+func (h Header) AllowsOperation(op KeyOperation) bool {
+	if h.Use != Unspecified && h.Use != op.usage() {
+		return false
+	}
+	return len(h.Ops) == 0 || slices.Contains(h.Ops, op)
+}
+
+// public returns a copy of the header for the public key.
+// Private key operations are mapped to their public counterparts.
+func (h Header) public() Header {
+	if len(h.Ops) == 0 {
+		return h
+	}
+
+	ops := make([]KeyOperation, 0, len(h.Ops))
+	for _, op := range h.Ops {
+		switch op {
+		case KeyOpSign:
+			op = KeyOpVerify
+		case KeyOpDecrypt:
+			op = KeyOpEncrypt
+		case KeyOpUnwrapKey:
+			op = KeyOpWrapKey
+		}
+
+		if !slices.Contains(ops, op) {
+			ops = append(ops, op)
+		}
+	}
+	h.Ops = ops
+	return h
+}
+
+// validateKeyOps checks that `key_ops` has no duplicates and is consistent with `use`.
+func (h Header) validateKeyOps() error {
+	for i, op := range h.Ops {
+		if !op.valid() {
+			return unknownKeyOperationErr(string(op))
+		} else if slices.Contains(h.Ops[:i], op) {
+			return fmt.Errorf("%w: duplicate key operation %s", ErrMalformedKey, op)
+		} else if h.Use != Unspecified && h.Use != op.usage() {
+			return fmt.Errorf("%w: key operation %s conflicts with use %s", ErrMalformedKey, op, h.Use)
+		}
+	}
+	return nil
+}
+
+func (h Header) Extensions() Extensions {
+	return h.Ext
+}
+
+// header gives access to the embedded header of a key for modification.
+func (h *Header) header() *Header {
+	return h
+}
+
+// writeJSON writes the opening brace and the shared claims into buf.
+// The object is left open, so the caller can append the key-specific claims.
+func (h Header) writeJSON(buf *bytes.Buffer, kty KeyType) {
+	buf.WriteString(`{"kty":`)
+	jsonenc.WriteString(buf, string(kty))
+
+	if h.Alg != "" {
+		writeStringMember(buf, ClaimAlg, h.Alg.String())
+	}
+	if h.Kid != "" {
+		writeStringMember(buf, ClaimKid, h.Kid)
+	}
+	if h.Use != Unspecified {
+		writeStringMember(buf, ClaimUse, string(h.Use))
+	}
+	if len(h.Ops) > 0 {
+		buf.WriteString(`,"key_ops":[`)
+		for i, op := range h.Ops {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			jsonenc.WriteString(buf, string(op))
+		}
+		buf.WriteByte(']')
+	}
+
+	h.writeX509JSON(buf)
+
+	h.Ext.writeJSON(buf)
+}
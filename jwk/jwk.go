@@ -2,6 +2,7 @@ package jwk
 
 import (
 	"crypto"
+	"crypto/x509"
 
 	"github.com/jgraeger/jwgo/jwa"
 )
@@ -15,8 +16,28 @@ type Key interface {
 	ID() string
 	// Usage returns the usage of the key (`use` claim)
 	Usage() KeyUsage
+	// KeyOps returns the permitted operations of the key (`key_ops` claim)
+	KeyOps() []KeyOperation
+	// AllowsOperation reports whether `use` and `key_ops` permit the operation.
+	AllowsOperation(op KeyOperation) bool
+	// X509CertChain returns the X.509 certificate chain (`x5c` claim)
+	X509CertChain() []*x509.Certificate
+	// X509Thumbprint returns the SHA-1 thumbprint of the leaf certificate (`x5t` claim)
+	X509Thumbprint() []byte
+	// X509ThumbprintS256 returns the SHA-256 thumbprint of the leaf certificate (`x5t#S256` claim)
+	X509ThumbprintS256() []byte
+	// X509URL returns the URL of the X.509 certificate chain (`x5u` claim)
+	X509URL() string
+	// Extensions returns the claims that are not interpreted by this package.
+	Extensions() Extensions
 	// Thumbprint returns the JWK thumbprint of the key.
 	Thumbprint(hash crypto.Hash) ([]byte, error)
+	// MarshalJSON serializes the key into a JWK.
+	MarshalJSON() ([]byte, error)
+	// Raw returns the underlying Go crypto key.
+	Raw() any
+	// PublicKey returns the public key, without any private claims.
+	PublicKey() (Key, error)
 }
 
 const (
@@ -24,6 +45,14 @@ const (
 	ClaimAlg = "alg"
 	ClaimUse = "use"
 	ClaimKid = "kid"
+	ClaimOth = "oth"
+
+	ClaimKeyOps = "key_ops"
+
+	ClaimX5c     = "x5c"
+	ClaimX5t     = "x5t"
+	ClaimX5tS256 = "x5t#S256"
+	ClaimX5u     = "x5u"
 )
 
 type KeyType string
@@ -32,11 +61,12 @@ const (
 	EC  KeyType = "EC"
 	RSA KeyType = "RSA"
 	OKP KeyType = "OKP"
+	Oct KeyType = "oct"
 )
 
 func (t KeyType) valid() bool {
 	switch t {
-	case EC, RSA, OKP:
+	case EC, RSA, OKP, Oct:
 		return true
 	default:
 		return false
@@ -60,3 +90,49 @@ func (u KeyUsage) valid() bool {
 		return false
 	}
 }
+
+// KeyOperation denotes an operation the key is intended for (RFC 7517, Section 4.3).
+type KeyOperation string
+
+const (
+	KeyOpSign       KeyOperation = "sign"
+	KeyOpVerify     KeyOperation = "verify"
+	KeyOpEncrypt    KeyOperation = "encrypt"
+	KeyOpDecrypt    KeyOperation = "decrypt"
+	KeyOpWrapKey    KeyOperation = "wrapKey"
+	KeyOpUnwrapKey  KeyOperation = "unwrapKey"
+	KeyOpDeriveKey  KeyOperation = "deriveKey"
+	KeyOpDeriveBits KeyOperation = "deriveBits"
+)
+
+func (op KeyOperation) valid() bool {
+	switch op {
+	case KeyOpSign, KeyOpVerify, KeyOpEncrypt, KeyOpDecrypt,
+		KeyOpWrapKey, KeyOpUnwrapKey, KeyOpDeriveKey, KeyOpDeriveBits:
+		return true
+	default:
+		return false
+	}
+}
+
+// usage returns the `use` value matching the operation.
+func (op KeyOperation) usage() KeyUsage {
+	switch op {
+	case KeyOpSign, KeyOpVerify:
+		return Signing
+	default:
+		return Encryption
+	}
+}
+
+// Curve denotes the curve of an elliptic curve key (`crv` claim).
+type Curve string
+
+const (
+	P256 Curve = "P-256"
+	P384 Curve = "P-384"
+	P521 Curve = "P-521"
+
+	Ed25519 Curve = "Ed25519"
+	X25519  Curve = "X25519"
+)
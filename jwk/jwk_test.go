@@ -3,6 +3,7 @@ package jwk_test
 import (
 	"crypto"
 	"encoding/base64"
+	"fmt"
 	"testing"
 
 	"github.com/jgraeger/jwgo/jwk"
@@ -81,6 +82,186 @@ func TestRSA(t *testing.T) {
 	})
 }
 
+func TestEC(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Thumbprint", func(t *testing.T) {
+		t.Parallel()
+		for _, tt := range []struct {
+			Name     string
+			JWK      string
+			Hash     crypto.Hash
+			expected []byte
+		}{
+			{
+				Name: "P-256 Public Key",
+				JWK: `{
+					"kty": "EC",
+					"alg": "ES256",
+					"crv": "P-256",
+					"x": "PBS-U2F4tvM0c9G9AlFwsq_-D-avJWC6vVm-VoeGzy4",
+					"y": "_nK1STaH_B6ySZR_4waibSjUuxdulspHHzMIbgsoExE"
+				}`,
+				Hash:     crypto.SHA256,
+				expected: MustBase64Decode(t, "W5_Xh5mRlwfmMZ1qaPAPy1e9GbtlW7HbyPF9OpNJQ5s"),
+			},
+			{
+				Name: "P-256 Private Key",
+				JWK: `{
+					"kty": "EC",
+					"alg": "ES256",
+					"crv": "P-256",
+					"x": "PBS-U2F4tvM0c9G9AlFwsq_-D-avJWC6vVm-VoeGzy4",
+					"y": "_nK1STaH_B6ySZR_4waibSjUuxdulspHHzMIbgsoExE",
+					"d": "7-lB0GFATuFdAbPf8DV0gzbJZRzejUwaJncSgTrW3Kw"
+				}`,
+				Hash:     crypto.SHA256,
+				expected: MustBase64Decode(t, "W5_Xh5mRlwfmMZ1qaPAPy1e9GbtlW7HbyPF9OpNJQ5s"),
+			},
+			{
+				Name: "P-384 Public Key",
+				JWK: `{
+					"kty": "EC",
+					"alg": "ES384",
+					"crv": "P-384",
+					"x": "3cfpEvtRJp2wlLdBKJ1ACBDwNS8aBa5JsBzsnJo_-AOqhsxftBN-IsKBigu8AM_W",
+					"y": "3y7iIyo-EWS5pdN_o-esGPuhX-G2yvyFYE9iR67QVTuGa86v6OcVNubpOHaSef3k"
+				}`,
+				Hash:     crypto.SHA256,
+				expected: MustBase64Decode(t, "t2siVo5JLj7JocKurVBb0VX595kA7nATJUBwfKKPw3c"),
+			},
+			{
+				Name: "P-521 Private Key",
+				JWK: `{
+					"kty": "EC",
+					"alg": "ES512",
+					"crv": "P-521",
+					"x": "AVAZs0IwZpL8eS9vtXJJ6Fio-frXSPihaXmG0zp5s-bObdIDNRB0g3TJeJmyUaFJsFLy-8GVJbXGwXzxssp7aK60",
+					"y": "AUjjY0aQBB5RonhggGvvNCO0NqeYfv9ccWh6LRgTmCf-_eEMSKHsLebhQ-cCsPQ4cT_0RF76GFveZLhRWJF1y7BC",
+					"d": "APb6tl9YNIfnpu4g2ruc1L0sc3cB9G-9YP7cxaKy-9j6y9PN78AQzmkKChbph6Gww7UJVOdbNJZ1Tn1IRGSo1VKo"
+				}`,
+				Hash:     crypto.SHA256,
+				expected: MustBase64Decode(t, "XE8LOXy6t_GoRRq0OzJ5GObG2kWS-NJ8eSo6EC2XYiI"),
+			},
+		} {
+			tc := tt
+			t.Run(tc.Name, func(t *testing.T) {
+				t.Parallel()
+
+				key, err := jwk.ParseString(tc.JWK)
+				require.NoError(t, err, "jwk.ParseKey should succeed")
+
+				tp, err := key.Thumbprint(tc.Hash)
+				require.NoError(t, err, "key.Thumbprint should succeed")
+
+				assert.Equal(t, tc.expected, tp, "calculated thumbprint should match the expected value")
+			})
+		}
+	})
+}
+
+func TestOKP(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Thumbprint", func(t *testing.T) {
+		t.Parallel()
+		for _, tt := range []struct {
+			Name     string
+			JWK      string
+			Hash     crypto.Hash
+			expected []byte
+		}{
+			// Test vector from RFC 8037, Appendix A.3
+			{
+				Name: "Ed25519 Public Key",
+				JWK: `{
+					"kty": "OKP",
+					"alg": "EdDSA",
+					"crv": "Ed25519",
+					"x": "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"
+				}`,
+				Hash:     crypto.SHA256,
+				expected: MustBase64Decode(t, "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k"),
+			},
+			{
+				Name: "Ed25519 Private Key",
+				JWK: `{
+					"kty": "OKP",
+					"alg": "EdDSA",
+					"crv": "Ed25519",
+					"d": "nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A",
+					"x": "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"
+				}`,
+				Hash:     crypto.SHA256,
+				expected: MustBase64Decode(t, "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k"),
+			},
+			// Bob's key from RFC 7748, Section 6.1
+			{
+				Name: "X25519 Private Key",
+				JWK: `{
+					"kty": "OKP",
+					"alg": "EdDSA",
+					"crv": "X25519",
+					"d": "XasIfmJKikt54X-Lg4AO5m87sSkmGLb9HC-LJ_-I4Os",
+					"x": "3p7bfXt9wbTTW2HC7OQ1Nz-DQ8hbeGdNrfx-FG-IK08"
+				}`,
+				Hash:     crypto.SHA256,
+				expected: MustBase64Decode(t, "giQqigT_IKcuzHl0FVJ3k5ts3_TWNAxvsC08UZsfcM8"),
+			},
+		} {
+			tc := tt
+			t.Run(tc.Name, func(t *testing.T) {
+				t.Parallel()
+
+				key, err := jwk.ParseString(tc.JWK)
+				require.NoError(t, err, "jwk.ParseKey should succeed")
+
+				tp, err := key.Thumbprint(tc.Hash)
+				require.NoError(t, err, "key.Thumbprint should succeed")
+
+				assert.Equal(t, tc.expected, tp, "calculated thumbprint should match the expected value")
+			})
+		}
+	})
+}
+
+func TestSymmetric(t *testing.T) {
+	t.Parallel()
+
+	const keyJSON = `{
+		"kty": "oct",
+		"alg": "HS256",
+		"kid": "hmac-1",
+		"k": "c3VwZXJzZWNyZXRzdXBlcnNlY3JldHN1cGVyc2VjcmV0ISE"
+	}`
+
+	t.Run("Thumbprint", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := jwk.ParseString(keyJSON)
+		require.NoError(t, err, "jwk.ParseKey should succeed")
+
+		tp, err := key.Thumbprint(crypto.SHA256)
+		require.NoError(t, err, "key.Thumbprint should succeed")
+
+		assert.Equal(t, MustBase64Decode(t, "BDrNdFlpbDyInLd34cnbtfgzz5SxOItSgD2ttwyyfLU"), tp)
+	})
+
+	t.Run("Format", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := jwk.ParseString(keyJSON)
+		require.NoError(t, err, "jwk.ParseKey should succeed")
+
+		for _, verb := range []string{"%v", "%+v", "%#v", "%s", "%x", "%q"} {
+			out := fmt.Sprintf(verb, key)
+			assert.NotContains(t, out, "supersecret", "formatting with %s should not leak the secret", verb)
+			assert.NotContains(t, out, "7375706572736563726574", "formatting with %s should not leak the secret", verb)
+			assert.Contains(t, out, "hmac-1", "formatting with %s should keep the header", verb)
+		}
+	})
+}
+
 func MustBase64Decode(t *testing.T, s string) []byte {
 	t.Helper()
 	res, err := base64.RawURLEncoding.DecodeString(s)
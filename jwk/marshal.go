@@ -0,0 +1,49 @@
+package jwk
+
+import (
+	"bytes"
+
+	"github.com/jgraeger/jwgo/internal/base64"
+	"github.com/jgraeger/jwgo/internal/jsonenc"
+	"github.com/jgraeger/jwgo/internal/pool"
+)
+
+// writeBase64Member writes `,"name":"<base64url(b)>"` into buf.
+func writeBase64Member(buf *bytes.Buffer, name string, b []byte) {
+	buf.WriteString(`,"`)
+	buf.WriteString(name)
+	buf.WriteString(`":"`)
+	writeBase64(buf, b)
+	buf.WriteByte('"')
+}
+
+// writeBase64 writes the unquoted base64url encoding of b into buf.
+func writeBase64(buf *bytes.Buffer, b []byte) {
+	// Encode directly into the buffer to avoid an intermediate string
+	n := base64.RawURLEncoding.EncodedLen(len(b))
+	buf.Grow(n)
+	dst := buf.AvailableBuffer()[:n]
+	base64.RawURLEncoding.Encode(dst, b)
+	buf.Write(dst)
+}
+
+// writeStringMember writes `,"name":"<s>"` into buf.
+func writeStringMember(buf *bytes.Buffer, name, s string) {
+	buf.WriteString(`,"`)
+	buf.WriteString(name)
+	buf.WriteString(`":`)
+	jsonenc.WriteString(buf, s)
+}
+
+// marshalKey serializes a key into a JWK. The shared claims are written first,
+// followed by the key-specific claims written by writeClaims.
+func marshalKey(h Header, kty KeyType, writeClaims func(buf *bytes.Buffer)) []byte {
+	buf := pool.GetBytesBuffer()
+	defer pool.PutBytesBuffer(buf)
+
+	h.writeJSON(buf, kty)
+	writeClaims(buf)
+	buf.WriteByte('}')
+
+	return bytes.Clone(buf.Bytes())
+}
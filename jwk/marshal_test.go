@@ -0,0 +1,147 @@
+package jwk_test
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/jgraeger/jwgo/jwk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRSAPrivateKeyJSON = `{
+	"kty": "RSA",
+	"alg": "RS256",
+	"kid": "rsa-1",
+	"use": "sig",
+	"n": "kMUCGoWr7_6rNzT5THxmHmBQw457ywXFxA9leV815SY9xGXir3KH4JRIm-jBn2k-eQ506RukOakCBldGDL1d4ZVKu7WzxIMScb79X-98BKfj8mS6kdz7gw6T8wCZ82zxjyKv0ePUd8ZaOVQqi3wrUPYa5dJgdO830NBoVf58b31reHEIpQRhEtT0FSQ5SFggieNoaWf6SWmxVKtScQm4Tuh0fyX_wXfjhAs66djFHUOTxJDICcpNmcKCDKU-gxo_NCpn3xZ51FNVShPqknclE05VKkL4iUu_1WBse0e1NeRhvfkuqrd11Ncx7X2QeXewc4w0iVtKmDeTU13ROj76rQ",
+	"e": "AQAB",
+	"d": "OsfjTMWNxIqRFn9p4gZ4qEjPQjfuR8b2P99IgnmINpzKY55C5p4IUcWjnbpqM8HV3e1ixuu0SL041z5EcRPKtLebepASh-34ZTr5QiTJJFLPGTKRFny1mscmh3ptCAvqIQYigYSSVnexVqm4BJ7ML7ldvocnJxOihCS62H_WIqYaqAmVQOrG6VCBq-QtiuXQP2YaNcO1G4CizWVK3kthYn3n8jXtsca4n9s8eIOZ7V5VemJVZ3MLQEQJHfBFHSorqI6U5a4aGK-hk4m7skgEG4-pMcIPP0SwmKeMSscA-zKk_sTZYWIZUXjNceLZDUrsgAYJ1GtCV5TzyO_RElzXuQ",
+	"p": "2XSYMo76V3B0uVypoTsgpigWsKGbxUTA6FeAMXDCeadMLlr5o-NzGZTjfI96r66SDZTQGegYtmoEKw1M0WQowAxPDwpJiimrDpAlEe_mAyEA9fUD6O6E4f9nQHz9kiNTQ0KrdxC-Itd9J4w--jnKI8ns0DI6XWCiZL4fDheXxdc",
+	"q": "qm4re5J4QEcLhYGRHHnvFTOoGzMMFBXc9qWy0CekjAEbTFEGZcKJ6aM7Z5Q5Yxk5EW0JZL-76otu5nBBwBfP1iMBfNkBMlQnJe23hE1VIsI4sy5JzpOtc1zyXubNoaz1l9buIIUQaBADo-CFJ0kCjsUc2Az2uVrWvePi8dTOKxs",
+	"dp": "Cct-r4hRLm8aUt8hpOmM5u8XVo1w_snCBrUqSQ_TMreebtgaNo-gN57FQG8WD6PFYGc7mG8j7dOIrIfE1gm07DGhvgOwnFCUK-vCP7SWn7101Z9btbpIsgVXGUiIA3Uj4vu1zX8rkVYzhPyEObEwsbv-tsIMbvhTWEZYD8JwS7E",
+	"dq": "kR6WL_acJj9YdCnLYjABgFAoCGEDG-cx62NUSyI2XnBiyi0EAYoQ3Lx9TMlNxDAqA8iQgxUv8Zsgp19W3TZpZrEQBzrQZgZ5_zXXWfRvVdWDai8z8Y6V1vGB_4UP-2bHCK-evFoRikp4jwYS20yzvNXipaUEQPg0eiSdjcXid5k",
+	"qi": "e7iS_Lbz6hfOse3naZKEaWr5oOK0vXhIALDGINzh3y7kBRg-Jb1MI-wawr9QgyIpxancuDrc9-Is1dqpz0lzlvs8TK8DFNriZGprinLjllECZgN-34RFWwMEB7E6lFEV41Nc7RzABpidEujtnzqTNBlv8-QbLl0PmT21_S48UlM"
+}`
+
+func TestMarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name    string
+		keyJSON string
+	}{
+		{
+			name:    "RSA private key",
+			keyJSON: testRSAPrivateKeyJSON,
+		},
+		{
+			name: "RSA public key",
+			keyJSON: `{
+				"kty": "RSA",
+				"alg": "RS256",
+				"e": "AQAB",
+				"n": "kMUCGoWr7_6rNzT5THxmHmBQw457ywXFxA9leV815SY9xGXir3KH4JRIm-jBn2k-eQ506RukOakCBldGDL1d4ZVKu7WzxIMScb79X-98BKfj8mS6kdz7gw6T8wCZ82zxjyKv0ePUd8ZaOVQqi3wrUPYa5dJgdO830NBoVf58b31reHEIpQRhEtT0FSQ5SFggieNoaWf6SWmxVKtScQm4Tuh0fyX_wXfjhAs66djFHUOTxJDICcpNmcKCDKU-gxo_NCpn3xZ51FNVShPqknclE05VKkL4iUu_1WBse0e1NeRhvfkuqrd11Ncx7X2QeXewc4w0iVtKmDeTU13ROj76rQ"
+			}`,
+		},
+		{
+			name: "EC private key",
+			keyJSON: `{
+				"kty": "EC",
+				"alg": "ES512",
+				"kid": "ec \"quoted\"\n",
+				"crv": "P-521",
+				"x": "AVAZs0IwZpL8eS9vtXJJ6Fio-frXSPihaXmG0zp5s-bObdIDNRB0g3TJeJmyUaFJsFLy-8GVJbXGwXzxssp7aK60",
+				"y": "AUjjY0aQBB5RonhggGvvNCO0NqeYfv9ccWh6LRgTmCf-_eEMSKHsLebhQ-cCsPQ4cT_0RF76GFveZLhRWJF1y7BC",
+				"d": "APb6tl9YNIfnpu4g2ruc1L0sc3cB9G-9YP7cxaKy-9j6y9PN78AQzmkKChbph6Gww7UJVOdbNJZ1Tn1IRGSo1VKo"
+			}`,
+		},
+		{
+			name: "OKP private key",
+			keyJSON: `{
+				"kty": "OKP",
+				"alg": "EdDSA",
+				"use": "sig",
+				"key_ops": ["sign", "verify"],
+				"crv": "Ed25519",
+				"d": "nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A",
+				"x": "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"
+			}`,
+		},
+		{
+			name: "oct key",
+			keyJSON: `{
+				"kty": "oct",
+				"alg": "HS256",
+				"kid": "é\u0001",
+				"k": "c3VwZXJzZWNyZXRzdXBlcnNlY3JldHN1cGVyc2VjcmV0ISE"
+			}`,
+		},
+	} {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			key, err := jwk.ParseString(tc.keyJSON)
+			require.NoError(t, err)
+
+			out, err := json.Marshal(key)
+			require.NoError(t, err, "json.Marshal should succeed")
+
+			var expected, actual map[string]any
+			require.NoError(t, json.Unmarshal([]byte(tc.keyJSON), &expected))
+			require.NoError(t, json.Unmarshal(out, &actual), "output should be valid JSON")
+			assert.Equal(t, expected, actual, "marshaled key should contain the same claims")
+		})
+	}
+}
+
+func TestMarshalJSONSet(t *testing.T) {
+	t.Parallel()
+
+	set, err := jwk.ParseSetString(testSetJSON)
+	require.NoError(t, err)
+
+	out, err := json.Marshal(set)
+	require.NoError(t, err)
+
+	roundTrip, err := jwk.ParseSet(out)
+	require.NoError(t, err)
+	assert.Equal(t, set.Len(), roundTrip.Len())
+}
+
+func TestMarshalJSONRSAPrimesNotCoprime(t *testing.T) {
+	t.Parallel()
+
+	p, _ := new(big.Int).SetString("170141183460469231731687303715884105727", 10) // 2^127-1
+	q := big.NewInt(2305843009213693951)                                          // 2^61-1
+
+	for _, tt := range []struct {
+		name   string
+		primes []*big.Int
+	}{
+		{name: "p equals q", primes: []*big.Int{p, p}},
+		{name: "repeated other prime", primes: []*big.Int{p, q, p}},
+	} {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			n := big.NewInt(1)
+			for _, prime := range tc.primes {
+				n.Mul(n, prime)
+			}
+			key, err := jwk.FromCrypto(&rsa.PrivateKey{
+				PublicKey: rsa.PublicKey{N: n, E: 65537},
+				D:         big.NewInt(3),
+				Primes:    tc.primes,
+			})
+			require.NoError(t, err)
+
+			_, err = json.Marshal(key)
+			assert.ErrorIs(t, err, jwk.ErrMalformedKey)
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package jwk
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"fmt"
+	"io"
+
+	"github.com/jgraeger/jwgo/internal/base64"
+	"github.com/jgraeger/jwgo/internal/pool"
+)
+
+// OKPPrivateKey is an octet key pair private key as defined in RFC 8037.
+type OKPPrivateKey struct {
+	Header
+	crv Curve
+	x   []byte
+	d   []byte
+}
+
+func (k OKPPrivateKey) Thumbprint(hash crypto.Hash) ([]byte, error) {
+	return okpThumbPrint(hash, k.crv, k.x)
+}
+
+// Curve returns the subtype of the key (`crv` claim).
+func (k OKPPrivateKey) Curve() Curve {
+	return k.crv
+}
+
+// Raw returns the underlying ed25519.PrivateKey or *ecdh.PrivateKey for X25519 keys.
+func (k OKPPrivateKey) Raw() any {
+	switch k.crv {
+	case Ed25519:
+		// An ed25519.PrivateKey is the seed followed by the public key
+		priv := make(ed25519.PrivateKey, 0, ed25519.PrivateKeySize)
+		return append(append(priv, k.d...), k.x...)
+	case X25519:
+		priv, err := ecdh.X25519().NewPrivateKey(k.d)
+		if err != nil {
+			return nil
+		}
+		return priv
+	default:
+		return nil
+	}
+}
+
+func (k OKPPrivateKey) PublicKey() (Key, error) {
+	return &OKPPublicKey{
+		Header: k.Header.public(),
+		crv:    k.crv,
+		x:      k.x,
+	}, nil
+}
+
+// Public implements crypto.Signer.
+func (k OKPPrivateKey) Public() crypto.PublicKey {
+	return okpRawPublicKey(k.crv, k.x)
+}
+
+// Sign implements crypto.Signer. Only Ed25519 keys can be used for signing.
+func (k OKPPrivateKey) Sign(rand io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if !k.AllowsOperation(KeyOpSign) {
+		return nil, operationNotPermittedErr(KeyOpSign)
+	}
+
+	priv, ok := k.Raw().(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("okp: %s keys cannot be used for signing", k.crv)
+	}
+	return priv.Sign(rand, message, opts)
+}
+
+func (k OKPPrivateKey) MarshalJSON() ([]byte, error) {
+	return marshalKey(k.Header, OKP, func(buf *bytes.Buffer) {
+		writeStringMember(buf, "crv", string(k.crv))
+		writeBase64Member(buf, "x", k.x)
+		writeBase64Member(buf, "d", k.d)
+	}), nil
+}
+
+// OKPPublicKey is an octet key pair public key as defined in RFC 8037.
+type OKPPublicKey struct {
+	Header
+	crv Curve
+	x   []byte
+}
+
+func (k OKPPublicKey) Thumbprint(hash crypto.Hash) ([]byte, error) {
+	return okpThumbPrint(hash, k.crv, k.x)
+}
+
+// Curve returns the subtype of the key (`crv` claim).
+func (k OKPPublicKey) Curve() Curve {
+	return k.crv
+}
+
+// Raw returns the underlying ed25519.PublicKey or *ecdh.PublicKey for X25519 keys.
+func (k OKPPublicKey) Raw() any {
+	return okpRawPublicKey(k.crv, k.x)
+}
+
+func (k OKPPublicKey) PublicKey() (Key, error) {
+	return &k, nil
+}
+
+// Public returns the underlying ed25519.PublicKey or *ecdh.PublicKey for X25519 keys.
+func (k OKPPublicKey) Public() crypto.PublicKey {
+	return okpRawPublicKey(k.crv, k.x)
+}
+
+func (k OKPPublicKey) MarshalJSON() ([]byte, error) {
+	return marshalKey(k.Header, OKP, func(buf *bytes.Buffer) {
+		writeStringMember(buf, "crv", string(k.crv))
+		writeBase64Member(buf, "x", k.x)
+	}), nil
+}
+
+// Interface guards
+var (
+	_ Key           = (*OKPPrivateKey)(nil)
+	_ Key           = (*OKPPublicKey)(nil)
+	_ crypto.Signer = (*OKPPrivateKey)(nil)
+)
+
+func okpRawPublicKey(crv Curve, x []byte) crypto.PublicKey {
+	switch crv {
+	case Ed25519:
+		return ed25519.PublicKey(x)
+	case X25519:
+		pub, err := ecdh.X25519().NewPublicKey(x)
+		if err != nil {
+			return nil
+		}
+		return pub
+	default:
+		return nil
+	}
+}
+
+func okpThumbPrint(hash crypto.Hash, crv Curve, x []byte) ([]byte, error) {
+	buf := pool.GetBytesBuffer()
+	defer pool.PutBytesBuffer(buf)
+
+	buf.WriteString(`{"crv":"`)
+	buf.WriteString(string(crv))
+	buf.WriteString(`","kty":"OKP","x":"`)
+	buf.WriteString(base64.RawURLEncoding.EncodeToString(x))
+	buf.WriteString(`"}`)
+
+	h := hash.New()
+	if _, err := buf.WriteTo(h); err != nil {
+		return nil, fmt.Errorf("write thumbprint to hash: %w", err)
+	}
+	return h.Sum(nil), nil
+}
+
+func (pk parsedJWK) toOKPKey(cfg parseConfig) (Key, error) {
+	crv := Curve(pk.k["crv"])
+	if crv != Ed25519 && crv != X25519 {
+		return nil, unknownCurveErr(string(crv))
+	}
+	size := crv.coordinateSize()
+
+	x, err := getBytesClaim(pk.k, "x")
+	if err != nil {
+		return nil, err
+	} else if len(x) != size {
+		return nil, fmt.Errorf("%w: okp: invalid public key size %d", ErrMalformedKey, len(x))
+	}
+
+	if _, ok := pk.k["d"]; !ok {
+		return &OKPPublicKey{
+			Header: pk.Header,
+			crv:    crv,
+			x:      x,
+		}, nil
+	}
+
+	d, err := getBytesClaim(pk.k, "d")
+	if err != nil {
+		return nil, err
+	} else if len(d) != size {
+		return nil, fmt.Errorf("%w: okp: invalid private key size %d", ErrMalformedKey, len(d))
+	}
+
+	if !cfg.skipValidation {
+		pub, err := okpPublicFromPrivate(crv, d)
+		if err != nil {
+			return nil, err
+		} else if !bytes.Equal(pub, x) {
+			return nil, fmt.Errorf("%w: okp: private key does not match public key", ErrMalformedKey)
+		}
+	}
+
+	return &OKPPrivateKey{
+		Header: pk.Header,
+		crv:    crv,
+		x:      x,
+		d:      d,
+	}, nil
+}
+
+// okpPublicFromPrivate derives the public key bytes from the private key bytes.
+func okpPublicFromPrivate(crv Curve, d []byte) ([]byte, error) {
+	switch crv {
+	case Ed25519:
+		//nolint:forcetypeassert
+		return ed25519.NewKeyFromSeed(d).Public().(ed25519.PublicKey), nil
+	case X25519:
+		priv, err := ecdh.X25519().NewPrivateKey(d)
+		if err != nil {
+			return nil, fmt.Errorf("%w: okp: invalid private key: %w", ErrMalformedKey, err)
+		}
+		return priv.PublicKey().Bytes(), nil
+	default:
+		return nil, unknownCurveErr(string(crv))
+	}
+}
@@ -0,0 +1,305 @@
+package jwk
+
+import (
+	"crypto"
+	"crypto/x509"
+	"time"
+
+	"github.com/jgraeger/jwgo/jwa"
+)
+
+// ParseOption configures the parsing of keys and key sets.
+type ParseOption func(*parseConfig)
+
+type parseConfig struct {
+	rejectUnsupported bool
+	rsaCRTValues      bool
+	rsaPrecompute     bool
+	skipValidation    bool
+
+	rejectDuplicates bool
+	rejectUnknown    bool
+	requireAlg       bool
+	inferAlg         bool
+	maxInputSize     int64
+	maxRSABits       int
+}
+
+func newParseConfig(opts []ParseOption) parseConfig {
+	var cfg parseConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithRejectUnsupportedKeys makes set parsing fail on keys with an unsupported
+// key type, curve or algorithm. By default such keys are skipped, as recommended
+// by RFC 7517, Section 5.
+func WithRejectUnsupportedKeys() ParseOption {
+	return func(c *parseConfig) {
+		c.rejectUnsupported = true
+	}
+}
+
+// WithRSACRTValues makes the parser use the `dp`, `dq` and `qi` claims of
+// RSA private keys instead of computing them. The values are checked against
+// the primes, unless validation is disabled.
+func WithRSACRTValues() ParseOption {
+	return func(c *parseConfig) {
+		c.rsaCRTValues = true
+	}
+}
+
+// WithRSAPrecompute validates RSA private keys and precomputes the values
+// used to speed up signing and decryption when parsing.
+func WithRSAPrecompute() ParseOption {
+	return func(c *parseConfig) {
+		c.rsaPrecompute = true
+	}
+}
+
+// WithoutValidation disables the consistency checks of key material,
+// e.g. whether an EC point is on the curve or a private key matches its public key.
+// Only use this for keys from a trusted source.
+func WithoutValidation() ParseOption {
+	return func(c *parseConfig) {
+		c.skipValidation = true
+	}
+}
+
+// WithRejectDuplicateMembers makes parsing fail on keys that contain a member more than once,
+// including members of nested objects.
+// By default the last occurrence of a member is used.
+func WithRejectDuplicateMembers() ParseOption {
+	return func(c *parseConfig) {
+		c.rejectDuplicates = true
+	}
+}
+
+// WithRejectUnknownMembers makes parsing fail on keys with members that are neither
+// registered header members nor claims of the key type, instead of keeping them as extensions.
+func WithRejectUnknownMembers() ParseOption {
+	return func(c *parseConfig) {
+		c.rejectUnknown = true
+	}
+}
+
+// WithRequireAlg makes parsing fail on keys without `alg` claim,
+// which is optional according to RFC 7517, Section 4.4.
+func WithRequireAlg() ParseOption {
+	return func(c *parseConfig) {
+		c.requireAlg = true
+	}
+}
+
+// WithInferAlg sets the `alg` claim of keys without one to the default algorithm
+// of the key type and curve, e.g. RS256 for RSA and ES384 for P-384 keys.
+// Symmetric keys of at least 32 bytes default to HS256. X25519 keys have no default.
+func WithInferAlg() ParseOption {
+	return func(c *parseConfig) {
+		c.inferAlg = true
+	}
+}
+
+// WithMaxInputSize limits the size of the JSON input in bytes.
+func WithMaxInputSize(n int64) ParseOption {
+	return func(c *parseConfig) {
+		c.maxInputSize = n
+	}
+}
+
+// WithMaxRSAModulusBits limits the size of the modulus of RSA keys in bits.
+func WithMaxRSAModulusBits(bits int) ParseOption {
+	return func(c *parseConfig) {
+		c.maxRSABits = bits
+	}
+}
+
+// KeyOption configures keys created from Go crypto keys.
+type KeyOption func(*keyConfig)
+
+type keyConfig struct {
+	kid            string
+	alg            jwa.KeyAlgorithm
+	use            KeyUsage
+	ops            []KeyOperation
+	certs          []*x509.Certificate
+	thumbprintHash crypto.Hash
+
+	// Only used to generate keys
+	rsaBits int
+	crv     Curve
+}
+
+func newKeyConfig(opts []KeyOption) keyConfig {
+	var cfg keyConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithKeyID sets the `kid` claim of the key.
+func WithKeyID(kid string) KeyOption {
+	return func(c *keyConfig) {
+		c.kid = kid
+	}
+}
+
+// WithAlgorithm sets the `alg` claim of the key.
+func WithAlgorithm(alg jwa.KeyAlgorithm) KeyOption {
+	return func(c *keyConfig) {
+		c.alg = alg
+	}
+}
+
+// WithUsage sets the `use` claim of the key.
+func WithUsage(use KeyUsage) KeyOption {
+	return func(c *keyConfig) {
+		c.use = use
+	}
+}
+
+// WithKeyOps sets the `key_ops` claim of the key.
+func WithKeyOps(ops ...KeyOperation) KeyOption {
+	return func(c *keyConfig) {
+		c.ops = ops
+	}
+}
+
+// WithX509CertChain sets the `x5c` claim of the key, with the leaf certificate first.
+// The leaf certificate must contain the public key.
+func WithX509CertChain(certs ...*x509.Certificate) KeyOption {
+	return func(c *keyConfig) {
+		c.certs = certs
+	}
+}
+
+// WithThumbprintKeyID sets the `kid` claim to the base64url encoded RFC 7638
+// thumbprint of the key, unless a key ID is set explicitly.
+func WithThumbprintKeyID(hash crypto.Hash) KeyOption {
+	return func(c *keyConfig) {
+		c.thumbprintHash = hash
+	}
+}
+
+// WithRSABits sets the size of generated RSA keys in bits.
+func WithRSABits(bits int) KeyOption {
+	return func(c *keyConfig) {
+		c.rsaBits = bits
+	}
+}
+
+// WithCurve sets the curve of generated EC and OKP keys.
+func WithCurve(crv Curve) KeyOption {
+	return func(c *keyConfig) {
+		c.crv = crv
+	}
+}
+
+// VerifyOption configures the verification of certificate chains.
+type VerifyOption func(*verifyConfig)
+
+type verifyConfig struct {
+	now            time.Time
+	keyUsages      []x509.ExtKeyUsage
+	dnsName        string
+	permittedNames []string
+}
+
+func newVerifyConfig(opts []VerifyOption) verifyConfig {
+	var cfg verifyConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithVerifyTime sets the time the certificates are checked against.
+// The current time is used by default.
+func WithVerifyTime(t time.Time) VerifyOption {
+	return func(c *verifyConfig) {
+		c.now = t
+	}
+}
+
+// WithExtKeyUsages requires the chain to be valid for one of the extended key usages.
+// By default any extended key usage is accepted.
+func WithExtKeyUsages(usages ...x509.ExtKeyUsage) VerifyOption {
+	return func(c *verifyConfig) {
+		c.keyUsages = usages
+	}
+}
+
+// WithDNSName requires the leaf certificate to be valid for the host name.
+func WithDNSName(name string) VerifyOption {
+	return func(c *verifyConfig) {
+		c.dnsName = name
+	}
+}
+
+// WithPermittedDNSDomains requires all DNS names of the certificates in the verified
+// chain to be one of the domains or a subdomain of them. The leaf certificate must
+// have at least one DNS name.
+func WithPermittedDNSDomains(domains ...string) VerifyOption {
+	return func(c *verifyConfig) {
+		c.permittedNames = domains
+	}
+}
+
+// CacheOption configures a Cache.
+type CacheOption func(*cacheConfig)
+
+type cacheConfig struct {
+	interval    time.Duration
+	minInterval time.Duration
+}
+
+func newCacheConfig(opts []CacheOption) cacheConfig {
+	cfg := cacheConfig{
+		interval:    DefaultRefreshInterval,
+		minInterval: DefaultMinRefreshInterval,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithRefreshInterval sets the maximum time a key set is cached.
+func WithRefreshInterval(d time.Duration) CacheOption {
+	return func(c *cacheConfig) {
+		c.interval = d
+	}
+}
+
+// WithMinRefreshInterval sets the minimum time between two fetches. It limits
+// refreshes caused by unknown key IDs and short cache lifetimes of responses.
+func WithMinRefreshInterval(d time.Duration) CacheOption {
+	return func(c *cacheConfig) {
+		c.minInterval = d
+	}
+}
+
+// HandlerOption configures a SetHandler.
+type HandlerOption func(*handlerConfig)
+
+type handlerConfig struct {
+	maxAge time.Duration
+}
+
+func newHandlerConfig(opts []HandlerOption) handlerConfig {
+	cfg := handlerConfig{maxAge: DefaultSetMaxAge}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithMaxAge sets the time clients may cache the served key set.
+func WithMaxAge(d time.Duration) HandlerOption {
+	return func(c *handlerConfig) {
+		c.maxAge = d
+	}
+}
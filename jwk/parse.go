@@ -2,10 +2,13 @@ package jwk
 
 import (
 	"bytes"
+	"crypto/sha1" //nolint:gosec // required for x5t
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"io"
 	"math/big"
+	"slices"
 	"strings"
 
 	"github.com/cristalhq/base64"
@@ -20,23 +23,49 @@ const (
 	parseMapSize = 8
 )
 
-func Parse(key []byte) (Key, error) {
-	return ParseReader(bytes.NewReader(key))
+func Parse(key []byte, opts ...ParseOption) (Key, error) {
+	return ParseReader(bytes.NewReader(key), opts...)
 }
 
-func ParseString(key string) (Key, error) {
-	return ParseReader(strings.NewReader(key))
+func ParseString(key string, opts ...ParseOption) (Key, error) {
+	return ParseReader(strings.NewReader(key), opts...)
 }
 
-func ParseReader(r io.Reader) (Key, error) {
-	p, err := parseKeyJSON(r)
+func ParseReader(r io.Reader, opts ...ParseOption) (Key, error) {
+	cfg := newParseConfig(opts)
+
+	r, err := cfg.limitInput(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseKey(json.NewDecoder(r), cfg)
+}
+
+// parseKey reads the next JWK object from the decoder and converts it into a key.
+func parseKey(dec *json.Decoder, cfg parseConfig) (Key, error) {
+	p, err := parseKeyJSON(dec, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrMalformedKey, err)
-	} else if p.Alg.String() == "" {
+	} else if p.Alg.String() == "" && cfg.requireAlg {
 		return nil, fmt.Errorf("%w: %s", ErrMalformedKey, "missing alg claim")
 	}
 
-	return p.toKey()
+	k, err := p.toKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if k.Algorithm() == "" && cfg.inferAlg {
+		//nolint:forcetypeassert
+		k.(interface{ header() *Header }).header().Alg = inferAlgorithm(k)
+	}
+
+	if !cfg.skipValidation {
+		if err := validateX509(k); err != nil {
+			return nil, err
+		}
+	}
+	return k, nil
 }
 
 type parsedClaims = map[string]string
@@ -54,70 +83,155 @@ func unmarshalBigIntClaim(claims parsedClaims, c string, dst *big.Int) error {
 		return errors.New("invalid destination pointer")
 	}
 
+	sb, err := getBytesClaim(claims, c)
+	if err != nil {
+		return err
+	}
+
+	dst.SetBytes(sb)
+	return nil
+}
+
+func getBytesClaim(claims parsedClaims, c string) ([]byte, error) {
 	s, ok := claims[c]
 	if !ok {
-		return fmt.Errorf("%w: rsa: %s", ErrMalformedKey, fmt.Sprintf("missing claim `%s`", c))
+		return nil, fmt.Errorf("%w: %s", ErrMalformedKey, fmt.Sprintf("missing claim `%s`", c))
 	}
 
 	sb, err := base64.RawURLEncoding.DecodeString(s)
 	if err != nil {
-		return fmt.Errorf("%w: rsa: failed to decode %s claim: %w", ErrMalformedKey, c, err)
+		return nil, fmt.Errorf("%w: failed to decode %s claim: %w", ErrMalformedKey, c, err)
 	}
 
-	dst.SetBytes(sb)
-	return nil
+	return sb, nil
+}
+
+// keyClaims lists the key-specific claims of every key type.
+var keyClaims = map[KeyType][]string{
+	RSA: {"n", "e", "d", "p", "q", "dp", "dq", "qi"},
+	EC:  {"crv", "x", "y", "d"},
+	OKP: {"crv", "x", "d"},
+	Oct: {"k"},
 }
 
 // parsedJWK is used ot hold a header and claims to be converted into a private key
 type parsedJWK struct {
 	Header
 	k parsedClaims
+	// oth holds the claims of additional primes of multi-prime RSA keys
+	oth []parsedClaims
+}
+
+func (p *parsedJWK) setExtension(name string, v any) {
+	if p.Ext == nil {
+		p.Ext = make(Extensions)
+	}
+	p.Ext[name] = v
 }
 
-func (p parsedJWK) toKey() (Key, error) {
+func (p parsedJWK) toKey(cfg parseConfig) (Key, error) {
 	switch p.Kty {
 	case RSA:
-		return p.toRSAKey()
+		return p.toRSAKey(cfg)
+	case EC:
+		return p.toECKey(cfg)
+	case OKP:
+		return p.toOKPKey(cfg)
+	case Oct:
+		return p.toSymmetricKey()
 	default:
 		return nil, fmt.Errorf("key type %s not supported", p.Kty)
 	}
 }
 
-func parseKeyJSON(r io.Reader) (p parsedJWK, err error) {
+// parseKeyJSON reads the next JSON object from the decoder.
+// The object is always consumed completely before the claims are validated,
+// so the decoder can continue with the next value if the key is rejected.
+func parseKeyJSON(dec *json.Decoder, cfg parseConfig) (p parsedJWK, err error) {
 	// Initialize parse struct
 	p.k = make(map[string]string, parseMapSize)
 
-	dec := json.NewDecoder(r)
-
 	// Read opening brace
 	_, err = readNextJSONDelimiter(dec)
 	if err != nil {
 		return p, fmt.Errorf("%w: %w", ErrMalformedJSON, err)
 	}
 
+	var (
+		alg  string
+		seen map[string]struct{}
+	)
+	if cfg.rejectDuplicates {
+		seen = make(map[string]struct{}, parseMapSize)
+	}
+
 	// Decode key
 	for dec.More() {
-		key, val, err := nextStringPair(dec)
+		key, err := nextStringToken(dec)
+		if err != nil {
+			return p, err
+		}
+
+		if seen != nil {
+			if _, ok := seen[key]; ok {
+				return p, fmt.Errorf("%w: `%s`", ErrDuplicateMember, key)
+			}
+			seen[key] = struct{}{}
+		}
+
+		if key == ClaimOth {
+			if p.oth, err = parseOtherPrimes(dec, cfg.rejectDuplicates); err != nil {
+				return p, err
+			}
+			continue
+		}
+
+		v, err := nextValue(dec, 0, cfg.rejectDuplicates)
 		if err != nil {
 			return p, err
 		}
 
+		val, isString := v.(string)
+		if !isString {
+			switch key {
+			case ClaimAlg, ClaimKty, ClaimUse, ClaimKid:
+				return p, fmt.Errorf("%w: claim `%s` must be a string, got %T", ErrMalformedJSON, key, v)
+			case ClaimKeyOps:
+				if p.Ops, err = toKeyOps(v); err != nil {
+					return p, err
+				}
+			case ClaimX5c:
+				if p.X5C, err = toCertChain(v); err != nil {
+					return p, err
+				}
+			default:
+				if cfg.rejectUnknown {
+					return p, fmt.Errorf("%w: `%s`", ErrUnknownMember, key)
+				}
+				p.setExtension(key, v)
+			}
+			continue
+		}
+
 		switch key {
-		case ClaimAlg:
-			p.Alg, err = jwa.KeyAlgorithmFrom(val)
-			if err != nil {
+		case ClaimKeyOps, ClaimX5c:
+			return p, fmt.Errorf("%w: claim `%s` must be an array", ErrMalformedJSON, key)
+		case ClaimX5t:
+			if p.X5T, err = decodeThumbprint(key, val, sha1.Size); err != nil {
+				return p, err
+			}
+		case ClaimX5tS256:
+			if p.X5TS256, err = decodeThumbprint(key, val, sha256.Size); err != nil {
 				return p, err
 			}
+		case ClaimX5u:
+			p.X5U = val
+		case ClaimAlg:
+			alg = val
 		case ClaimKty:
 			p.Kty = KeyType(val)
-			if !p.Kty.valid() {
-				return p, unknownKeyTypeErr(val)
-			}
 		case ClaimUse:
 			p.Use = KeyUsage(val)
-			if !p.Use.valid() {
-				return p, unknownKeyUseErr(val)
-			}
 		case ClaimKid:
 			p.Kid = val
 		default:
@@ -131,9 +245,59 @@ func parseKeyJSON(r io.Reader) (p parsedJWK, err error) {
 		return p, fmt.Errorf("%w: %w", ErrMalformedJSON, err)
 	}
 
+	if !p.Kty.valid() {
+		return p, unknownKeyTypeErr(string(p.Kty))
+	}
+
+	if alg != "" {
+		p.Alg, err = jwa.KeyAlgorithmFrom(alg)
+		if err != nil {
+			return p, err
+		}
+	}
+
+	if p.Use != Unspecified && !p.Use.valid() {
+		return p, unknownKeyUseErr(string(p.Use))
+	}
+
+	if err := p.validateKeyOps(); err != nil {
+		return p, err
+	}
+
+	if cfg.rejectUnknown && len(p.oth) > 0 && p.Kty != RSA {
+		return p, fmt.Errorf("%w: `%s`", ErrUnknownMember, ClaimOth)
+	}
+
+	// String claims not defined for the key type are extensions
+	for name, val := range p.k {
+		if !slices.Contains(keyClaims[p.Kty], name) {
+			if cfg.rejectUnknown {
+				return p, fmt.Errorf("%w: `%s`", ErrUnknownMember, name)
+			}
+			p.setExtension(name, val)
+			delete(p.k, name)
+		}
+	}
+
 	return p, nil
 }
 
+// limitInput reads the input of r if a maximum input size is set.
+// The JSON decoder reads ahead, so the input is read completely to detect larger input.
+func (c parseConfig) limitInput(r io.Reader) (io.Reader, error) {
+	if c.maxInputSize <= 0 {
+		return r, nil
+	}
+
+	b, err := io.ReadAll(io.LimitReader(r, c.maxInputSize+1))
+	if err != nil {
+		return nil, err
+	} else if int64(len(b)) > c.maxInputSize {
+		return nil, fmt.Errorf("%w: exceeds %d bytes", ErrInputTooLarge, c.maxInputSize)
+	}
+	return bytes.NewReader(b), nil
+}
+
 func readNextJSONDelimiter(dec *json.Decoder) (json.Delim, error) {
 	t, err := dec.Token()
 	if err != nil {
@@ -148,18 +312,134 @@ func readNextJSONDelimiter(dec *json.Decoder) (json.Delim, error) {
 	return d, nil
 }
 
-func nextStringPair(dec *json.Decoder) (key, val string, err error) {
-	key, err = nextStringToken(dec)
-	if err != nil {
-		return "", "", err
+// toKeyOps converts the decoded `key_ops` array.
+func toKeyOps(v any) ([]KeyOperation, error) {
+	arr, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: claim `%s` must be an array", ErrMalformedJSON, ClaimKeyOps)
+	}
+
+	ops := make([]KeyOperation, len(arr))
+	for i, op := range arr {
+		s, ok := op.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: claim `%s` must contain strings", ErrMalformedJSON, ClaimKeyOps)
+		}
+		ops[i] = KeyOperation(s)
+	}
+	return ops, nil
+}
+
+// maxRSAOtherPrimes is the maximum number of entries of the `oth` claim.
+// Multi-prime keys use only a few primes, more would make parsing and validation expensive.
+const maxRSAOtherPrimes = 14
+
+// parseOtherPrimes reads the `oth` claim of multi-prime RSA keys,
+// an array of objects with the string members `r`, `d` and `t`.
+func parseOtherPrimes(dec *json.Decoder, rejectDuplicates bool) ([]parsedClaims, error) {
+	if d, err := readNextJSONDelimiter(dec); err != nil || d != '[' {
+		return nil, fmt.Errorf("%w: claim `%s` must be an array", ErrMalformedJSON, ClaimOth)
+	}
+
+	var oth []parsedClaims
+	for dec.More() {
+		if len(oth) == maxRSAOtherPrimes {
+			return nil, fmt.Errorf("%w: rsa: more than %d other primes", ErrMalformedKey, maxRSAOtherPrimes)
+		}
+		if d, err := readNextJSONDelimiter(dec); err != nil || d != '{' {
+			return nil, fmt.Errorf("%w: claim `%s` must contain objects", ErrMalformedJSON, ClaimOth)
+		}
+
+		claims := make(parsedClaims, 3)
+		for dec.More() {
+			name, err := nextStringToken(dec)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := claims[name]; ok && rejectDuplicates {
+				return nil, fmt.Errorf("%w: `%s.%s`", ErrDuplicateMember, ClaimOth, name)
+			}
+
+			val, err := nextStringToken(dec)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %w", ErrMalformedJSON, ClaimOth, err)
+			}
+			claims[name] = val
+		}
+
+		if _, err := readNextJSONDelimiter(dec); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrMalformedJSON, err)
+		}
+		oth = append(oth, claims)
 	}
 
-	val, err = nextStringToken(dec)
+	if _, err := readNextJSONDelimiter(dec); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedJSON, err)
+	}
+	return oth, nil
+}
+
+// maxValueDepth is the maximum nesting of arrays and objects within a key.
+// Values are decoded recursively, so the depth is limited to protect the stack.
+const maxValueDepth = 64
+
+// nextValue reads the next JSON value at the nesting depth from the decoder.
+// Arrays and objects are decoded into []any and map[string]any.
+func nextValue(dec *json.Decoder, depth int, rejectDuplicates bool) (any, error) {
+	t, err := dec.Token()
 	if err != nil {
-		return "", "", err
+		return nil, err
+	}
+
+	d, ok := t.(json.Delim)
+	if !ok {
+		return t, nil
 	}
+	if depth >= maxValueDepth {
+		return nil, fmt.Errorf("%w: values are nested deeper than %d levels", ErrMalformedKey, maxValueDepth)
+	}
+
+	switch d {
+	case '[':
+		arr := []any{}
+		for dec.More() {
+			v, err := nextValue(dec, depth+1, rejectDuplicates)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+
+		if _, err := readNextJSONDelimiter(dec); err != nil {
+			return nil, err
+		}
+		return arr, nil
+	case '{':
+		obj := map[string]any{}
+		for dec.More() {
+			k, err := nextStringToken(dec)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := obj[k]; ok && rejectDuplicates {
+				return nil, fmt.Errorf("%w: `%s`", ErrDuplicateMember, k)
+			}
+
+			v, err := nextValue(dec, depth+1, rejectDuplicates)
+			if err != nil {
+				return nil, err
+			}
+			obj[k] = v
+		}
 
-	return key, val, nil
+		if _, err := readNextJSONDelimiter(dec); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	default:
+		return nil, fmt.Errorf("unexpected delimiter %s", d)
+	}
 }
 
 func nextStringToken(dec *json.Decoder) (string, error) {
@@ -1,11 +1,20 @@
 package jwk_test
 
 import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/jgraeger/jwgo/jwa"
 	"github.com/jgraeger/jwgo/jwk"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParse(t *testing.T) {
@@ -35,6 +44,109 @@ func TestParse(t *testing.T) {
 				assert.True(t, isRSAPubKey)
 			},
 		},
+		{
+			name: "valid EC private key",
+			keyJSON: `{
+				"kty": "EC",
+				"alg": "ES256",
+				"kid": "ec-1",
+				"crv": "P-256",
+				"x": "PBS-U2F4tvM0c9G9AlFwsq_-D-avJWC6vVm-VoeGzy4",
+				"y": "_nK1STaH_B6ySZR_4waibSjUuxdulspHHzMIbgsoExE",
+				"d": "7-lB0GFATuFdAbPf8DV0gzbJZRzejUwaJncSgTrW3Kw"
+			}`,
+			assertions: func(k jwk.Key) {
+				assert.Equal(t, jwk.EC, k.Type())
+				assert.Equal(t, "ec-1", k.ID())
+
+				_, isECPrivKey := k.(*jwk.ECPrivateKey)
+				assert.True(t, isECPrivKey)
+			},
+		},
+		{
+			name: "EC point not on curve",
+			keyJSON: `{
+				"kty": "EC",
+				"alg": "ES256",
+				"crv": "P-256",
+				"x": "PBS-U2F4tvM0c9G9AlFwsq_-D-avJWC6vVm-VoeGzy4",
+				"y": "_nK1STaH_B6ySZR_4waibSjUuxdulspHHzMIbgsoExA"
+			}`,
+			expectedErr: jwk.ErrMalformedKey,
+		},
+		{
+			name: "EC private key does not match public key",
+			keyJSON: `{
+				"kty": "EC",
+				"alg": "ES256",
+				"crv": "P-256",
+				"x": "PBS-U2F4tvM0c9G9AlFwsq_-D-avJWC6vVm-VoeGzy4",
+				"y": "_nK1STaH_B6ySZR_4waibSjUuxdulspHHzMIbgsoExE",
+				"d": "7-lB0GFATuFdAbPf8DV0gzbJZRzejUwaJncSgTrW3Ka"
+			}`,
+			expectedErr: jwk.ErrMalformedKey,
+		},
+		{
+			name: "EC unknown curve",
+			keyJSON: `{
+				"kty": "EC",
+				"alg": "ES256K",
+				"crv": "secp256k1",
+				"x": "PBS-U2F4tvM0c9G9AlFwsq_-D-avJWC6vVm-VoeGzy4",
+				"y": "_nK1STaH_B6ySZR_4waibSjUuxdulspHHzMIbgsoExE"
+			}`,
+			expectedErr: jwk.ErrUnknownCurve,
+		},
+		{
+			name: "valid OKP public key",
+			keyJSON: `{
+				"kty": "OKP",
+				"alg": "EdDSA",
+				"crv": "Ed25519",
+				"x": "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"
+			}`,
+			assertions: func(k jwk.Key) {
+				assert.Equal(t, jwk.OKP, k.Type())
+
+				okp, isOKPPubKey := k.(*jwk.OKPPublicKey)
+				assert.True(t, isOKPPubKey)
+				assert.Equal(t, jwk.Ed25519, okp.Curve())
+			},
+		},
+		{
+			name: "OKP private key does not match public key",
+			keyJSON: `{
+				"kty": "OKP",
+				"alg": "EdDSA",
+				"crv": "Ed25519",
+				"d": "XasIfmJKikt54X-Lg4AO5m87sSkmGLb9HC-LJ_-I4Os",
+				"x": "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"
+			}`,
+			expectedErr: jwk.ErrMalformedKey,
+		},
+		{
+			name: "valid oct key",
+			keyJSON: `{
+				"kty": "oct",
+				"alg": "HS256",
+				"k": "c3VwZXJzZWNyZXRzdXBlcnNlY3JldHN1cGVyc2VjcmV0ISE"
+			}`,
+			assertions: func(k jwk.Key) {
+				assert.Equal(t, jwk.Oct, k.Type())
+
+				_, isSymmetricKey := k.(*jwk.SymmetricKey)
+				assert.True(t, isSymmetricKey)
+			},
+		},
+		{
+			name: "oct key too short for algorithm",
+			keyJSON: `{
+				"kty": "oct",
+				"alg": "HS512",
+				"k": "c3VwZXJzZWNyZXRzdXBlcnNlY3JldHN1cGVyc2VjcmV0ISE"
+			}`,
+			expectedErr: jwk.ErrMalformedKey,
+		},
 	} {
 		tc := tt
 		t.Run(tc.name, func(t *testing.T) {
@@ -53,3 +165,501 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRSAOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRT values are ignored by default", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := jwk.ParseString(testRSAPrivateKeyJSON)
+		require.NoError(t, err)
+
+		//nolint:forcetypeassert
+		assert.Nil(t, key.Raw().(*rsa.PrivateKey).Precomputed.Dp)
+	})
+
+	t.Run("WithRSACRTValues", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := jwk.ParseString(testRSAPrivateKeyJSON, jwk.WithRSACRTValues())
+		require.NoError(t, err)
+
+		//nolint:forcetypeassert
+		priv := key.Raw().(*rsa.PrivateKey)
+		assert.NotNil(t, priv.Precomputed.Dp)
+		assert.NotNil(t, priv.Precomputed.Dq)
+		assert.NotNil(t, priv.Precomputed.Qinv)
+	})
+
+	t.Run("WithRSACRTValues rejects inconsistent values", func(t *testing.T) {
+		t.Parallel()
+
+		tampered := strings.Replace(testRSAPrivateKeyJSON, `"dp": "Cct`, `"dp": "Dct`, 1)
+		_, err := jwk.ParseString(tampered, jwk.WithRSACRTValues())
+		assert.ErrorIs(t, err, jwk.ErrMalformedKey)
+
+		_, err = jwk.ParseString(tampered, jwk.WithRSACRTValues(), jwk.WithoutValidation())
+		assert.NoError(t, err, "inconsistent values are accepted without validation")
+	})
+
+	t.Run("WithRSAPrecompute", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := jwk.ParseString(testRSAPrivateKeyJSON, jwk.WithRSAPrecompute())
+		require.NoError(t, err)
+
+		//nolint:forcetypeassert
+		priv := key.Raw().(*rsa.PrivateKey)
+		assert.NotNil(t, priv.Precomputed.Dp)
+	})
+
+	t.Run("WithRSAPrecompute validates the key", func(t *testing.T) {
+		t.Parallel()
+
+		tampered := strings.Replace(testRSAPrivateKeyJSON, `"d": "Osf`, `"d": "Psf`, 1)
+		_, err := jwk.ParseString(tampered, jwk.WithRSAPrecompute())
+		assert.ErrorIs(t, err, jwk.ErrMalformedKey)
+	})
+}
+
+func TestParseWithoutValidation(t *testing.T) {
+	t.Parallel()
+
+	const offCurve = `{
+		"kty": "EC",
+		"alg": "ES256",
+		"crv": "P-256",
+		"x": "PBS-U2F4tvM0c9G9AlFwsq_-D-avJWC6vVm-VoeGzy4",
+		"y": "_nK1STaH_B6ySZR_4waibSjUuxdulspHHzMIbgsoExA"
+	}`
+
+	_, err := jwk.ParseString(offCurve)
+	require.ErrorIs(t, err, jwk.ErrMalformedKey)
+
+	_, err = jwk.ParseString(offCurve, jwk.WithoutValidation())
+	assert.NoError(t, err)
+}
+
+func TestParseMultiPrimeRSA(t *testing.T) {
+	t.Parallel()
+
+	//nolint:staticcheck // multi-prime keys are deprecated, but still found in the wild
+	raw, err := rsa.GenerateMultiPrimeKey(rand.Reader, 3, 2048)
+	require.NoError(t, err)
+
+	key, err := jwk.FromCrypto(raw, jwk.WithAlgorithm(jwa.KeyAlgorithmMustFrom(jwa.RS256)))
+	require.NoError(t, err)
+
+	b, err := key.MarshalJSON()
+	require.NoError(t, err)
+
+	var claims map[string]any
+	require.NoError(t, json.Unmarshal(b, &claims))
+	require.Contains(t, claims, "oth")
+	require.Len(t, claims["oth"], 1)
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+
+		parsed, err := jwk.Parse(b, jwk.WithRSACRTValues(), jwk.WithRSAPrecompute())
+		require.NoError(t, err)
+
+		//nolint:forcetypeassert
+		priv := parsed.Raw().(*rsa.PrivateKey)
+		assert.Equal(t, raw.Primes, priv.Primes)
+		assert.Len(t, priv.Precomputed.CRTValues, 1)
+
+		digest := make([]byte, 32)
+		sig, err := rsa.SignPKCS1v15(rand.Reader, priv, crypto.SHA256, digest)
+		require.NoError(t, err)
+		assert.NoError(t, rsa.VerifyPKCS1v15(&raw.PublicKey, crypto.SHA256, digest, sig))
+	})
+
+	t.Run("inconsistent CRT values", func(t *testing.T) {
+		t.Parallel()
+
+		//nolint:forcetypeassert
+		oth := claims["oth"].([]any)[0].(map[string]any)
+		tampered := strings.Replace(string(b), `"t":"`+oth["t"].(string), `"t":"`+oth["d"].(string), 1)
+
+		_, err := jwk.ParseString(tampered, jwk.WithRSACRTValues())
+		assert.ErrorIs(t, err, jwk.ErrMalformedKey)
+	})
+
+	t.Run("primes do not match modulus", func(t *testing.T) {
+		t.Parallel()
+
+		//nolint:forcetypeassert
+		oth := claims["oth"].([]any)[0].(map[string]any)
+		tampered := strings.Replace(string(b), `"r":"`+oth["r"].(string), `"r":"AQAB`, 1)
+
+		_, err := jwk.ParseString(tampered)
+		assert.ErrorIs(t, err, jwk.ErrMalformedKey)
+	})
+}
+
+func TestParseRSAPrimesNotCoprime(t *testing.T) {
+	t.Parallel()
+
+	p, _ := new(big.Int).SetString("170141183460469231731687303715884105727", 10) // 2^127-1
+	q := big.NewInt(2305843009213693951)                                          // 2^61-1
+	b64 := func(v *big.Int) string {
+		return base64.RawURLEncoding.EncodeToString(v.Bytes())
+	}
+	keyJSON := func(primes ...*big.Int) string {
+		n := big.NewInt(1)
+		for _, prime := range primes {
+			n.Mul(n, prime)
+		}
+		key := fmt.Sprintf(`{"kty":"RSA","n":"%s","e":"AQAB","d":"Aw","p":"%s","q":"%s","dp":"AQ","dq":"AQ","qi":"AQ"`,
+			b64(n), b64(primes[0]), b64(primes[1]))
+		if len(primes) > 2 {
+			key += fmt.Sprintf(`,"oth":[{"r":"%s","d":"AQ","t":"AQ"}]`, b64(primes[2]))
+		}
+		return key + "}"
+	}
+
+	for _, tt := range []struct {
+		name    string
+		keyJSON string
+	}{
+		{name: "p equals q", keyJSON: keyJSON(p, p)},
+		{name: "repeated other prime", keyJSON: keyJSON(p, q, p)},
+		{name: "other prime equals q", keyJSON: keyJSON(q, p, p)},
+	} {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := jwk.ParseString(tc.keyJSON)
+			assert.ErrorIs(t, err, jwk.ErrMalformedKey)
+			_, err = jwk.ParseString(tc.keyJSON, jwk.WithRSACRTValues())
+			assert.ErrorIs(t, err, jwk.ErrMalformedKey)
+			_, err = jwk.ParseString(tc.keyJSON, jwk.WithRSACRTValues(), jwk.WithoutValidation())
+			assert.ErrorIs(t, err, jwk.ErrMalformedKey)
+		})
+	}
+}
+
+func TestParseExtensions(t *testing.T) {
+	t.Parallel()
+
+	const keyJSON = `{
+		"kty": "EC",
+		"alg": "ES256",
+		"kid": "ec-1",
+		"crv": "P-256",
+		"x": "PBS-U2F4tvM0c9G9AlFwsq_-D-avJWC6vVm-VoeGzy4",
+		"y": "_nK1STaH_B6ySZR_4waibSjUuxdulspHHzMIbgsoExE",
+		"k": "not an EC claim",
+		"tags": ["a", "b"],
+		"nested": {"enabled": true, "list": [1, null, {"x": "y"}]},
+		"version": 2
+	}`
+
+	key, err := jwk.ParseString(keyJSON)
+	require.NoError(t, err)
+
+	ext := key.Extensions()
+	s, ok := ext.String("k")
+	assert.True(t, ok)
+	assert.Equal(t, "not an EC claim", s)
+
+	tags, ok := ext.Strings("tags")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"a", "b"}, tags)
+
+	nested, ok := ext.Get("nested")
+	assert.True(t, ok)
+	assert.Equal(t, map[string]any{
+		"enabled": true,
+		"list":    []any{float64(1), nil, map[string]any{"x": "y"}},
+	}, nested)
+
+	_, ok = ext.Get("x")
+	assert.False(t, ok, "key claims are not extensions")
+
+	// Extensions survive a serialization round trip
+	b, err := key.MarshalJSON()
+	require.NoError(t, err)
+
+	var expected, actual map[string]any
+	require.NoError(t, json.Unmarshal([]byte(keyJSON), &expected))
+	require.NoError(t, json.Unmarshal(b, &actual))
+	assert.Equal(t, expected, actual)
+
+	t.Run("registered claims must be strings", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := jwk.ParseString(`{"kty": "oct", "kid": 1, "k": "AQAB"}`)
+		assert.ErrorIs(t, err, jwk.ErrMalformedJSON)
+	})
+
+	t.Run("nesting depth is limited", func(t *testing.T) {
+		t.Parallel()
+
+		nested := func(depth int) string {
+			return `{"kty": "oct", "k": "AQAB", "x": ` + strings.Repeat("[", depth) + strings.Repeat("]", depth) + `}`
+		}
+
+		_, err := jwk.ParseString(nested(64))
+		assert.NoError(t, err)
+
+		_, err = jwk.ParseString(nested(65))
+		assert.ErrorIs(t, err, jwk.ErrMalformedKey)
+
+		_, err = jwk.ParseString(nested(100000))
+		assert.ErrorIs(t, err, jwk.ErrMalformedKey)
+	})
+}
+
+func TestParseKeyOps(t *testing.T) {
+	t.Parallel()
+
+	const keyFmt = `{
+		"kty": "OKP",
+		"alg": "EdDSA",
+		"crv": "Ed25519",
+		"d": "nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A",
+		"x": "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"
+		%s
+	}`
+
+	for _, tt := range []struct {
+		name        string
+		claims      string
+		expectedOps []jwk.KeyOperation
+		expectedErr error
+	}{
+		{name: "no key_ops"},
+		{
+			name:        "valid key_ops",
+			claims:      `, "key_ops": ["sign", "verify"]`,
+			expectedOps: []jwk.KeyOperation{jwk.KeyOpSign, jwk.KeyOpVerify},
+		},
+		{
+			name:        "key_ops consistent with use",
+			claims:      `, "use": "sig", "key_ops": ["sign"]`,
+			expectedOps: []jwk.KeyOperation{jwk.KeyOpSign},
+		},
+		{
+			name:        "key_ops conflicting with use",
+			claims:      `, "use": "enc", "key_ops": ["sign"]`,
+			expectedErr: jwk.ErrMalformedKey,
+		},
+		{
+			name:        "duplicate key_ops",
+			claims:      `, "key_ops": ["sign", "sign"]`,
+			expectedErr: jwk.ErrMalformedKey,
+		},
+		{
+			name:        "unknown key_ops",
+			claims:      `, "key_ops": ["launch"]`,
+			expectedErr: jwk.ErrUnknownKeyOperation,
+		},
+		{
+			name:        "key_ops is not an array",
+			claims:      `, "key_ops": "sign"`,
+			expectedErr: jwk.ErrMalformedJSON,
+		},
+	} {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			key, err := jwk.ParseString(fmt.Sprintf(keyFmt, tc.claims))
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedOps, key.KeyOps())
+		})
+	}
+}
+
+func TestParseStrict(t *testing.T) {
+	t.Parallel()
+
+	const (
+		ecKey = `{
+			"kty": "EC",
+			"alg": "ES256",
+			"crv": "P-256",
+			"x": "PBS-U2F4tvM0c9G9AlFwsq_-D-avJWC6vVm-VoeGzy4",
+			"y": "_nK1STaH_B6ySZR_4waibSjUuxdulspHHzMIbgsoExE"
+			%s
+		}`
+		rsaPublicKey = `{
+			"kty": "RSA",
+			"alg": "RS256",
+			"n": "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+			"e": "AQAB"
+		}`
+	)
+
+	for _, tt := range []struct {
+		name        string
+		keyJSON     string
+		opts        []jwk.ParseOption
+		expectedErr error
+	}{
+		{
+			name:    "duplicate member",
+			keyJSON: fmt.Sprintf(ecKey, `, "alg": "ES384"`),
+		},
+		{
+			name:        "duplicate member rejected",
+			keyJSON:     fmt.Sprintf(ecKey, `, "alg": "ES384"`),
+			opts:        []jwk.ParseOption{jwk.WithRejectDuplicateMembers()},
+			expectedErr: jwk.ErrDuplicateMember,
+		},
+		{
+			name:    "duplicate nested member",
+			keyJSON: fmt.Sprintf(ecKey, `, "ext": [{"a": 1, "a": 2}], "oth": [{"r": "AQ", "r": "Aw"}]`),
+		},
+		{
+			name:        "duplicate member of extension rejected",
+			keyJSON:     fmt.Sprintf(ecKey, `, "ext": [{"a": 1, "a": 2}]`),
+			opts:        []jwk.ParseOption{jwk.WithRejectDuplicateMembers()},
+			expectedErr: jwk.ErrDuplicateMember,
+		},
+		{
+			name:        "duplicate member of other prime rejected",
+			keyJSON:     fmt.Sprintf(ecKey, `, "oth": [{"r": "AQ", "r": "Aw"}]`),
+			opts:        []jwk.ParseOption{jwk.WithRejectDuplicateMembers()},
+			expectedErr: jwk.ErrDuplicateMember,
+		},
+		{
+			name:        "too many other primes",
+			keyJSON:     fmt.Sprintf(ecKey, `, "oth": [`+strings.Repeat(`{"r": "AQ"},`, 14)+`{"r": "AQ"}]`),
+			expectedErr: jwk.ErrMalformedKey,
+		},
+		{
+			name:    "unknown members",
+			keyJSON: fmt.Sprintf(ecKey, `, "n": "AQAB", "iat": 1`),
+		},
+		{
+			name:        "unknown string member rejected",
+			keyJSON:     fmt.Sprintf(ecKey, `, "n": "AQAB"`),
+			opts:        []jwk.ParseOption{jwk.WithRejectUnknownMembers()},
+			expectedErr: jwk.ErrUnknownMember,
+		},
+		{
+			name:        "unknown member rejected",
+			keyJSON:     fmt.Sprintf(ecKey, `, "iat": 1`),
+			opts:        []jwk.ParseOption{jwk.WithRejectUnknownMembers()},
+			expectedErr: jwk.ErrUnknownMember,
+		},
+		{
+			name:    "registered members accepted",
+			keyJSON: fmt.Sprintf(ecKey, `, "kid": "1", "use": "sig", "key_ops": ["verify"], "x5u": "https://example.com"`),
+			opts:    []jwk.ParseOption{jwk.WithRejectUnknownMembers(), jwk.WithRejectDuplicateMembers()},
+		},
+		{
+			name:    "input within size limit",
+			keyJSON: fmt.Sprintf(ecKey, ""),
+			opts:    []jwk.ParseOption{jwk.WithMaxInputSize(int64(len(fmt.Sprintf(ecKey, ""))))},
+		},
+		{
+			name:        "input exceeds size limit",
+			keyJSON:     fmt.Sprintf(ecKey, ""),
+			opts:        []jwk.ParseOption{jwk.WithMaxInputSize(64)},
+			expectedErr: jwk.ErrInputTooLarge,
+		},
+		{
+			name:    "modulus within size limit",
+			keyJSON: rsaPublicKey,
+			opts:    []jwk.ParseOption{jwk.WithMaxRSAModulusBits(2048)},
+		},
+		{
+			name:        "modulus exceeds size limit",
+			keyJSON:     rsaPublicKey,
+			opts:        []jwk.ParseOption{jwk.WithMaxRSAModulusBits(1024)},
+			expectedErr: jwk.ErrMalformedKey,
+		},
+		{
+			name:    "missing alg",
+			keyJSON: strings.Replace(fmt.Sprintf(ecKey, ""), `"alg": "ES256",`, "", 1),
+		},
+		{
+			name:        "missing alg required",
+			keyJSON:     strings.Replace(fmt.Sprintf(ecKey, ""), `"alg": "ES256",`, "", 1),
+			opts:        []jwk.ParseOption{jwk.WithRequireAlg()},
+			expectedErr: jwk.ErrMalformedKey,
+		},
+	} {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := jwk.ParseString(tc.keyJSON, tc.opts...)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestParseInferAlg(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name        string
+		keyJSON     string
+		expectedAlg string
+	}{
+		{
+			name:        "RSA",
+			keyJSON:     `{"kty":"RSA","n":"0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw","e":"AQAB"}`,
+			expectedAlg: "RS256",
+		},
+		{
+			name:        "EC P-384",
+			keyJSON:     `{"kty":"EC","crv":"P-384","x":"3cfpEvtRJp2wlLdBKJ1ACBDwNS8aBa5JsBzsnJo_-AOqhsxftBN-IsKBigu8AM_W","y":"3y7iIyo-EWS5pdN_o-esGPuhX-G2yvyFYE9iR67QVTuGa86v6OcVNubpOHaSef3k"}`,
+			expectedAlg: "ES384",
+		},
+		{
+			name:        "Ed25519",
+			keyJSON:     `{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`,
+			expectedAlg: "EdDSA",
+		},
+		{
+			name:    "X25519",
+			keyJSON: `{"kty":"OKP","crv":"X25519","x":"3p7bfXt9wbTTW2HC7OQ1Nz-DQ8hbeGdNrfx-FG-IK08"}`,
+		},
+		{
+			name:        "oct",
+			keyJSON:     `{"kty":"oct","k":"c3VwZXJzZWNyZXRzdXBlcnNlY3JldHN1cGVyc2VjcmV0"}`,
+			expectedAlg: "HS256",
+		},
+		{
+			name:    "short oct",
+			keyJSON: `{"kty":"oct","k":"c3VwZXJzZWNyZXQ"}`,
+		},
+		{
+			name:        "explicit alg",
+			keyJSON:     `{"kty":"oct","alg":"HS512","k":"c3VwZXJzZWNyZXRzdXBlcnNlY3JldHN1cGVyc2VjcmV0c3VwZXJzZWNyZXRzdXBlcnNlY3JldHN1cGVyc2VjcmV0c3VwZXJzZWM"}`,
+			expectedAlg: "HS512",
+		},
+	} {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			key, err := jwk.ParseString(tc.keyJSON)
+			require.NoError(t, err)
+			if !strings.Contains(tc.keyJSON, `"alg"`) {
+				assert.Empty(t, key.Algorithm().String())
+			}
+
+			key, err = jwk.ParseString(tc.keyJSON, jwk.WithInferAlg())
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedAlg, key.Algorithm().String())
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package jwk
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+)
+
+// PEM block types
+const (
+	pemPrivateKey    = "PRIVATE KEY"
+	pemRSAPrivateKey = "RSA PRIVATE KEY"
+	pemECPrivateKey  = "EC PRIVATE KEY"
+	pemPublicKey     = "PUBLIC KEY"
+	pemRSAPublicKey  = "RSA PUBLIC KEY"
+	pemCertificate   = "CERTIFICATE"
+)
+
+// ParsePEM parses all keys of PEM encoded data.
+// Supported are PKCS #8, PKCS #1 and SEC 1 private keys and PKIX and PKCS #1 public keys.
+// Consecutive CERTIFICATE blocks form a certificate chain, which is set as `x5c` claim
+// of the key matching the leaf certificate. Chains without a matching key
+// are returned as public key of the leaf certificate. Other blocks are ignored.
+func ParsePEM(data []byte, opts ...KeyOption) ([]Key, error) {
+	var (
+		raw    []any
+		chains [][]*x509.Certificate
+		inCert bool
+	)
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		if block.Type != pemCertificate {
+			inCert = false
+
+			key, err := parsePEMBlock(block)
+			if err != nil {
+				return nil, err
+			} else if key != nil {
+				raw = append(raw, key)
+			}
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrMalformedKey, err)
+		}
+		if !inCert {
+			chains = append(chains, nil)
+			inCert = true
+		}
+		chains[len(chains)-1] = append(chains[len(chains)-1], cert)
+	}
+
+	if len(raw) == 0 && len(chains) == 0 {
+		return nil, fmt.Errorf("%w: no keys found in PEM data", ErrMalformedKey)
+	}
+
+	cfg := newKeyConfig(opts)
+	keys := make([]Key, 0, len(raw)+len(chains))
+	for _, key := range raw {
+		k, err := fromCrypto(key)
+		if err != nil {
+			return nil, err
+		}
+
+		keyCfg := cfg
+		if len(keyCfg.certs) == 0 {
+			keyCfg.certs, chains = takeMatchingChain(k, chains)
+		}
+		if err := keyCfg.apply(k); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+
+	for _, chain := range chains {
+		k, err := fromCertificates(chain, cfg)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+
+	return keys, nil
+}
+
+// ParseDER parses a DER encoded key or certificate.
+// Supported are the same formats as for ParsePEM.
+func ParseDER(der []byte, opts ...KeyOption) (Key, error) {
+	cfg := newKeyConfig(opts)
+
+	for _, parse := range []func([]byte) (any, error){
+		x509.ParsePKCS8PrivateKey,
+		func(der []byte) (any, error) { return x509.ParsePKCS1PrivateKey(der) },
+		func(der []byte) (any, error) { return x509.ParseECPrivateKey(der) },
+		x509.ParsePKIXPublicKey,
+		func(der []byte) (any, error) { return x509.ParsePKCS1PublicKey(der) },
+	} {
+		key, err := parse(der)
+		if err != nil {
+			continue
+		}
+
+		k, err := fromCrypto(key)
+		if err != nil {
+			return nil, err
+		}
+		if err := cfg.apply(k); err != nil {
+			return nil, err
+		}
+		return k, nil
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unsupported DER encoding", ErrMalformedKey)
+	}
+	return fromCertificates([]*x509.Certificate{cert}, cfg)
+}
+
+// EncodePEM encodes the key as PEM, using PKCS #8 for private keys and PKIX for
+// public keys. The certificates of the `x5c` claim are appended as CERTIFICATE blocks.
+// Symmetric keys cannot be encoded.
+func EncodePEM(k Key) ([]byte, error) {
+	var (
+		block pem.Block
+		err   error
+	)
+	switch k.(type) {
+	case *RSAPrivateKey, *ECPrivateKey, *OKPPrivateKey:
+		block.Type = pemPrivateKey
+		block.Bytes, err = x509.MarshalPKCS8PrivateKey(k.Raw())
+	default:
+		var pub crypto.PublicKey
+		if pub, err = PublicKeyOf(k); err != nil {
+			return nil, err
+		}
+		block.Type = pemPublicKey
+		block.Bytes, err = x509.MarshalPKIXPublicKey(pub)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encode %s key: %w", k.Type(), err)
+	}
+
+	var buf bytes.Buffer
+	if err := pem.Encode(&buf, &block); err != nil {
+		return nil, err
+	}
+	for _, cert := range k.X509CertChain() {
+		if err := pem.Encode(&buf, &pem.Block{Type: pemCertificate, Bytes: cert.Raw}); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// parsePEMBlock parses the key of a PEM block. Blocks of unknown type result in a nil key.
+func parsePEMBlock(block *pem.Block) (key any, err error) {
+	switch block.Type {
+	case pemPrivateKey:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case pemRSAPrivateKey:
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case pemECPrivateKey:
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case pemPublicKey:
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case pemRSAPublicKey:
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrMalformedKey, block.Type, err)
+	}
+	return key, nil
+}
+
+// takeMatchingChain removes the first chain whose leaf certificate contains the public key of k.
+func takeMatchingChain(k Key, chains [][]*x509.Certificate) ([]*x509.Certificate, [][]*x509.Certificate) {
+	pub, err := PublicKeyOf(k)
+	if err != nil {
+		return nil, chains
+	}
+
+	for i, chain := range chains {
+		if leafContainsKey(chain[0], pub) {
+			return chain, append(chains[:i:i], chains[i+1:]...)
+		}
+	}
+	return nil, chains
+}
+
+// fromCertificates creates the public key of the leaf certificate of a chain.
+func fromCertificates(chain []*x509.Certificate, cfg keyConfig) (Key, error) {
+	k, err := fromCrypto(chain[0].PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfg.certs) == 0 {
+		cfg.certs = chain
+	}
+	if err := cfg.apply(k); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
@@ -0,0 +1,211 @@
+package jwk_test
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/jgraeger/jwgo/jwk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePEM(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	xKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	mustDER := func(der []byte, err error) []byte {
+		require.NoError(t, err)
+		return der
+	}
+
+	for _, tt := range []struct {
+		name        string
+		blockType   string
+		der         []byte
+		expectedRaw any
+	}{
+		{
+			name:        "RSA PKCS #1 private key",
+			blockType:   "RSA PRIVATE KEY",
+			der:         x509.MarshalPKCS1PrivateKey(rsaKey),
+			expectedRaw: rsaKey,
+		},
+		{
+			name:        "RSA PKCS #8 private key",
+			blockType:   "PRIVATE KEY",
+			der:         mustDER(x509.MarshalPKCS8PrivateKey(rsaKey)),
+			expectedRaw: rsaKey,
+		},
+		{
+			name:        "RSA PKCS #1 public key",
+			blockType:   "RSA PUBLIC KEY",
+			der:         x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey),
+			expectedRaw: &rsaKey.PublicKey,
+		},
+		{
+			name:        "RSA PKIX public key",
+			blockType:   "PUBLIC KEY",
+			der:         mustDER(x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)),
+			expectedRaw: &rsaKey.PublicKey,
+		},
+		{
+			name:        "EC SEC 1 private key",
+			blockType:   "EC PRIVATE KEY",
+			der:         mustDER(x509.MarshalECPrivateKey(ecKey)),
+			expectedRaw: ecKey,
+		},
+		{
+			name:        "EC PKCS #8 private key",
+			blockType:   "PRIVATE KEY",
+			der:         mustDER(x509.MarshalPKCS8PrivateKey(ecKey)),
+			expectedRaw: ecKey,
+		},
+		{
+			name:        "EC PKIX public key",
+			blockType:   "PUBLIC KEY",
+			der:         mustDER(x509.MarshalPKIXPublicKey(&ecKey.PublicKey)),
+			expectedRaw: &ecKey.PublicKey,
+		},
+		{
+			name:        "Ed25519 private key",
+			blockType:   "PRIVATE KEY",
+			der:         mustDER(x509.MarshalPKCS8PrivateKey(edKey)),
+			expectedRaw: edKey,
+		},
+		{
+			name:        "Ed25519 public key",
+			blockType:   "PUBLIC KEY",
+			der:         mustDER(x509.MarshalPKIXPublicKey(edKey.Public())),
+			expectedRaw: edKey.Public(),
+		},
+		{
+			name:        "X25519 private key",
+			blockType:   "PRIVATE KEY",
+			der:         mustDER(x509.MarshalPKCS8PrivateKey(xKey)),
+			expectedRaw: xKey,
+		},
+		{
+			name:        "X25519 public key",
+			blockType:   "PUBLIC KEY",
+			der:         mustDER(x509.MarshalPKIXPublicKey(xKey.PublicKey())),
+			expectedRaw: xKey.PublicKey(),
+		},
+	} {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			keys, err := jwk.ParsePEM(pem.EncodeToMemory(&pem.Block{Type: tc.blockType, Bytes: tc.der}))
+			require.NoError(t, err)
+			require.Len(t, keys, 1)
+			assertRawEqual(t, tc.expectedRaw, keys[0].Raw())
+
+			key, err := jwk.ParseDER(tc.der)
+			require.NoError(t, err)
+			assertRawEqual(t, tc.expectedRaw, key.Raw())
+
+			// Round trip
+			encoded, err := jwk.EncodePEM(key)
+			require.NoError(t, err)
+			keys, err = jwk.ParsePEM(encoded)
+			require.NoError(t, err)
+			require.Len(t, keys, 1)
+			assertRawEqual(t, tc.expectedRaw, keys[0].Raw())
+		})
+	}
+}
+
+func assertRawEqual(t *testing.T, expected, actual any) {
+	t.Helper()
+
+	switch expected := expected.(type) {
+	case interface{ Equal(crypto.PrivateKey) bool }:
+		assert.True(t, expected.Equal(actual), "expected %T to be equal", expected)
+	case interface{ Equal(crypto.PublicKey) bool }:
+		assert.True(t, expected.Equal(actual), "expected %T to be equal", expected)
+	default:
+		t.Fatalf("%T cannot be compared", expected)
+	}
+}
+
+func TestParsePEMCertificates(t *testing.T) {
+	t.Parallel()
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	cert := newTestCertificate(t, priv)
+	otherCert := newTestCertificate(t, other)
+
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+
+	// The chain of the other certificate is separated from the first chain by the private key
+	var data []byte
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "EC PARAMETERS", Bytes: []byte{0x06}})...)
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})...)
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: otherCert.Raw})...)
+
+	keys, err := jwk.ParsePEM(data, jwk.WithKeyID("pem"))
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+
+	// Private key with the matching chain
+	assert.IsType(t, &jwk.ECPrivateKey{}, keys[0])
+	assert.Equal(t, "pem", keys[0].ID())
+	assert.Equal(t, []*x509.Certificate{cert}, keys[0].X509CertChain())
+
+	// Public key of the unmatched chain
+	assert.IsType(t, &jwk.ECPublicKey{}, keys[1])
+	assert.Equal(t, []*x509.Certificate{otherCert}, keys[1].X509CertChain())
+	assert.Equal(t, &other.PublicKey, keys[1].Raw())
+
+	encoded, err := jwk.EncodePEM(keys[0])
+	require.NoError(t, err)
+	block, rest := pem.Decode(encoded)
+	assert.Equal(t, "PRIVATE KEY", block.Type)
+	block, _ = pem.Decode(rest)
+	assert.Equal(t, "CERTIFICATE", block.Type)
+	assert.Equal(t, cert.Raw, block.Bytes)
+
+	key, err := jwk.ParseDER(cert.Raw)
+	require.NoError(t, err)
+	assert.Equal(t, []*x509.Certificate{cert}, key.X509CertChain())
+}
+
+func TestParsePEMErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := jwk.ParsePEM([]byte("not PEM"))
+	assert.ErrorIs(t, err, jwk.ErrMalformedKey)
+
+	_, err = jwk.ParsePEM(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("garbage")}))
+	assert.ErrorIs(t, err, jwk.ErrMalformedKey)
+
+	_, err = jwk.ParseDER([]byte("garbage"))
+	assert.ErrorIs(t, err, jwk.ErrMalformedKey)
+
+	key, err := jwk.FromCrypto([]byte("supersecret"))
+	require.NoError(t, err)
+	_, err = jwk.EncodePEM(key)
+	assert.ErrorIs(t, err, jwk.ErrNoPublicKey)
+}
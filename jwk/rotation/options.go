@@ -0,0 +1,79 @@
+package rotation
+
+import (
+	"time"
+
+	"github.com/jgraeger/jwgo/jwk"
+)
+
+// Option configures a Manager.
+type Option func(*config)
+
+type config struct {
+	kty            jwk.KeyType
+	keyOpts        []jwk.KeyOption
+	rotationPeriod time.Duration
+	prePublication time.Duration
+	retention      time.Duration
+	clock          Clock
+	publisher      Publisher
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		kty:            jwk.EC,
+		rotationPeriod: DefaultRotationPeriod,
+		prePublication: DefaultPrePublication,
+		retention:      DefaultRetention,
+		clock:          systemClock{},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithKeyType sets the type and options of generated keys. By default P-256 keys are used.
+func WithKeyType(kty jwk.KeyType, opts ...jwk.KeyOption) Option {
+	return func(c *config) {
+		c.kty = kty
+		c.keyOpts = opts
+	}
+}
+
+// WithRotationPeriod sets the time a key is current.
+func WithRotationPeriod(d time.Duration) Option {
+	return func(c *config) {
+		c.rotationPeriod = d
+	}
+}
+
+// WithPrePublication sets the minimum time a key is published before it becomes current.
+// It should exceed the time verifiers cache the key set.
+func WithPrePublication(d time.Duration) Option {
+	return func(c *config) {
+		c.prePublication = d
+	}
+}
+
+// WithRetention sets the time a key is published after it was current.
+// It should exceed the lifetime of tokens signed with the key.
+func WithRetention(d time.Duration) Option {
+	return func(c *config) {
+		c.retention = d
+	}
+}
+
+// WithClock sets the clock used to schedule rotations.
+func WithClock(clock Clock) Option {
+	return func(c *config) {
+		c.clock = clock
+	}
+}
+
+// WithPublisher publishes the key set after every change.
+func WithPublisher(p Publisher) Option {
+	return func(c *config) {
+		c.publisher = p
+	}
+}
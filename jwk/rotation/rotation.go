@@ -0,0 +1,269 @@
+// Package rotation implements the rotation of signing keys.
+//
+// A Manager keeps keys in three phases. The current key is used for signing.
+// The next key is published ahead of its use, so verifiers have fetched it
+// when it becomes current. Retiring keys are published until tokens signed
+// with them have expired.
+package rotation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jgraeger/jwgo/jwk"
+)
+
+const (
+	// DefaultRotationPeriod is the time a key is current, unless set by WithRotationPeriod.
+	DefaultRotationPeriod = 30 * 24 * time.Hour
+	// DefaultPrePublication is the minimum time a key is published before it becomes current,
+	// unless set by WithPrePublication.
+	DefaultPrePublication = 24 * time.Hour
+	// DefaultRetention is the time a key is published after it was current, unless set by WithRetention.
+	DefaultRetention = 24 * time.Hour
+)
+
+var ErrInvalidSchedule = errors.New("invalid rotation schedule")
+
+// Phase is the phase of a key in the rotation.
+type Phase string
+
+const (
+	Next     Phase = "next"
+	Current  Phase = "current"
+	Retiring Phase = "retiring"
+)
+
+// Entry is a key in the rotation.
+type Entry struct {
+	Key   jwk.Key
+	Phase Phase
+	// Since is the time the key entered the phase.
+	Since time.Time
+}
+
+// Storage persists the keys of a rotation. Loading and storing the keys is not
+// atomic, so only one Manager may use a storage. Managers sharing a storage may
+// generate different keys and overwrite each other's keys.
+type Storage interface {
+	// Load returns the stored entries, or no entries if nothing was stored yet.
+	Load(ctx context.Context) ([]Entry, error)
+	// Store replaces the stored entries.
+	Store(ctx context.Context, entries []Entry) error
+}
+
+// Publisher publishes the key set of a rotation, e.g. a jwk.SetHandler.
+type Publisher interface {
+	Store(set *jwk.Set) error
+}
+
+// Clock returns the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Manager rotates keys on a schedule.
+type Manager struct {
+	storage Storage
+	cfg     config
+
+	mu      sync.RWMutex
+	entries []Entry
+}
+
+// NewManager creates a manager for the keys in the storage. Missing keys are generated
+// and keys are rotated if they are due.
+func NewManager(ctx context.Context, storage Storage, opts ...Option) (*Manager, error) {
+	cfg := newConfig(opts)
+	if cfg.prePublication > cfg.rotationPeriod {
+		return nil, fmt.Errorf("%w: pre-publication of %s exceeds rotation period of %s", ErrInvalidSchedule, cfg.prePublication, cfg.rotationPeriod)
+	}
+
+	m := &Manager{storage: storage, cfg: cfg}
+	if err := m.Rotate(ctx); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// SigningKey returns the current key.
+func (m *Manager) SigningKey() jwk.Key {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, e := range m.entries {
+		if e.Phase == Current {
+			return e.Key
+		}
+	}
+	return nil
+}
+
+// Set returns the current, next and retiring keys, in this order.
+// The keys include private key material, which jwk.SetHandler removes.
+func (m *Manager) Set() *jwk.Set {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return newSet(m.entries)
+}
+
+// Entries returns a copy of the keys in the rotation.
+func (m *Manager) Entries() []Entry {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.entries)
+}
+
+// Rotate loads the keys from the storage and advances them according to the schedule:
+// Retiring keys are removed after the retention. Once the current key reaches the
+// rotation period, it is retired and the next key becomes current, if it was
+// published for the pre-publication time. A new next key is generated if needed.
+// Changes are stored and published.
+func (m *Manager) Rotate(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries, err := m.storage.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("load keys: %w", err)
+	}
+
+	rotated, changed, err := m.advance(entries)
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		if err := m.storage.Store(ctx, rotated); err != nil {
+			return fmt.Errorf("store keys: %w", err)
+		}
+	}
+
+	// The keys are published whenever they differ from the last published keys,
+	// as they may have been changed in the storage.
+	// They are only used once published, so a failed publication is retried.
+	if m.cfg.publisher != nil && !slices.EqualFunc(rotated, m.entries, sameEntry) {
+		if err := m.cfg.publisher.Store(newSet(rotated)); err != nil {
+			return fmt.Errorf("publish keys: %w", err)
+		}
+	}
+	m.entries = rotated
+	return nil
+}
+
+// Run rotates the keys at the interval until ctx is done.
+// Errors are passed to onError, if it is not nil. Run returns immediately
+// if the interval is not positive.
+func (m *Manager) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	if interval <= 0 {
+		if onError != nil {
+			onError(fmt.Errorf("%w: interval of %s is not positive", ErrInvalidSchedule, interval))
+		}
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := m.Rotate(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+// sameEntry reports whether the entries have the same key, phase and start time.
+func sameEntry(a, b Entry) bool {
+	return a.Key.ID() == b.Key.ID() && a.Phase == b.Phase && a.Since.Equal(b.Since)
+}
+
+func newSet(entries []Entry) *jwk.Set {
+	keys := make([]jwk.Key, len(entries))
+	for i, e := range entries {
+		keys[i] = e.Key
+	}
+	return jwk.NewSet(keys...)
+}
+
+// advance applies the schedule to the entries and reports whether they changed.
+func (m *Manager) advance(entries []Entry) ([]Entry, bool, error) {
+	now := m.cfg.clock.Now()
+
+	var (
+		current, next *Entry
+		retiring      []Entry
+		changed       bool
+	)
+	for i := range entries {
+		e := &entries[i]
+		switch e.Phase {
+		case Current:
+			current = e
+		case Next:
+			next = e
+		case Retiring:
+			if now.Sub(e.Since) >= m.cfg.retention {
+				changed = true
+				continue
+			}
+			retiring = append(retiring, *e)
+		default:
+			return nil, false, fmt.Errorf("key %s has unknown phase %q", e.Key.ID(), e.Phase)
+		}
+	}
+
+	switch {
+	case current == nil && next != nil:
+		// The next key is used right away, as there is no key to sign with
+		current = &Entry{Key: next.Key, Phase: Current, Since: now}
+		next = nil
+		changed = true
+	case current == nil:
+		k, err := m.generate()
+		if err != nil {
+			return nil, false, err
+		}
+		current = &Entry{Key: k, Phase: Current, Since: now}
+		changed = true
+	case next != nil && now.Sub(current.Since) >= m.cfg.rotationPeriod && now.Sub(next.Since) >= m.cfg.prePublication:
+		retiring = append(retiring, Entry{Key: current.Key, Phase: Retiring, Since: now})
+		current = &Entry{Key: next.Key, Phase: Current, Since: now}
+		next = nil
+		changed = true
+	}
+
+	if next == nil {
+		k, err := m.generate()
+		if err != nil {
+			return nil, false, err
+		}
+		next = &Entry{Key: k, Phase: Next, Since: now}
+		changed = true
+	}
+
+	return append([]Entry{*current, *next}, retiring...), changed, nil
+}
+
+func (m *Manager) generate() (jwk.Key, error) {
+	k, err := jwk.Generate(m.cfg.kty, m.cfg.keyOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("generate key: %w", err)
+	}
+	return k, nil
+}
@@ -0,0 +1,260 @@
+package rotation_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jgraeger/jwgo/jwk"
+	"github.com/jgraeger/jwgo/jwk/rotation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func phases(m *rotation.Manager) map[rotation.Phase][]string {
+	p := make(map[rotation.Phase][]string)
+	for _, e := range m.Entries() {
+		p[e.Phase] = append(p[e.Phase], e.Key.ID())
+	}
+	return p
+}
+
+func TestManager(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name    string
+		storage func(t *testing.T) rotation.Storage
+	}{
+		{
+			name:    "memory storage",
+			storage: func(*testing.T) rotation.Storage { return rotation.NewMemoryStorage() },
+		},
+		{
+			name: "file storage",
+			storage: func(t *testing.T) rotation.Storage {
+				return rotation.NewFileStorage(filepath.Join(t.TempDir(), "keys.json"))
+			},
+		},
+	} {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+			storage := tc.storage(t)
+			h, err := jwk.NewSetHandler(jwk.NewSet())
+			require.NoError(t, err)
+
+			opts := []rotation.Option{
+				rotation.WithClock(clock),
+				rotation.WithKeyType(jwk.OKP),
+				rotation.WithRotationPeriod(24 * time.Hour),
+				rotation.WithPrePublication(time.Hour),
+				rotation.WithRetention(2 * time.Hour),
+				rotation.WithPublisher(h),
+			}
+			m, err := rotation.NewManager(ctx, storage, opts...)
+			require.NoError(t, err)
+
+			initial := phases(m)
+			require.Len(t, initial[rotation.Current], 1)
+			require.Len(t, initial[rotation.Next], 1)
+			assert.Empty(t, initial[rotation.Retiring])
+			assert.Equal(t, initial[rotation.Current][0], m.SigningKey().ID())
+			assert.Equal(t, 2, m.Set().Len())
+
+			// Not due yet
+			clock.Advance(23 * time.Hour)
+			require.NoError(t, m.Rotate(ctx))
+			assert.Equal(t, initial, phases(m))
+
+			// The next key becomes current and the current key is retired
+			clock.Advance(time.Hour)
+			require.NoError(t, m.Rotate(ctx))
+			rotated := phases(m)
+			assert.Equal(t, initial[rotation.Next], rotated[rotation.Current])
+			assert.Equal(t, initial[rotation.Current], rotated[rotation.Retiring])
+			require.Len(t, rotated[rotation.Next], 1)
+			assert.Equal(t, rotated[rotation.Current][0], m.SigningKey().ID())
+
+			// All three keys are published
+			set := m.Set()
+			assert.Equal(t, 3, set.Len())
+			for _, kid := range []string{initial[rotation.Current][0], rotated[rotation.Current][0], rotated[rotation.Next][0]} {
+				_, ok := set.LookupKeyID(kid)
+				assert.True(t, ok, kid)
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			published, err := jwk.ParseSet(rec.Body.Bytes())
+			require.NoError(t, err)
+			assert.Equal(t, 3, published.Len())
+
+			// A manager using the same storage continues the rotation
+			restored, err := rotation.NewManager(ctx, storage, opts...)
+			require.NoError(t, err)
+			assert.Equal(t, rotated, phases(restored))
+
+			// The retiring key is removed after the retention
+			clock.Advance(2 * time.Hour)
+			require.NoError(t, m.Rotate(ctx))
+			retired := phases(m)
+			assert.Empty(t, retired[rotation.Retiring])
+			assert.Equal(t, rotated[rotation.Current], retired[rotation.Current])
+			assert.Equal(t, rotated[rotation.Next], retired[rotation.Next])
+		})
+	}
+}
+
+func TestManagerPublishing(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	storage := rotation.NewMemoryStorage()
+
+	// Keys which were not published long enough are not used for signing
+	m, err := rotation.NewManager(ctx, storage,
+		rotation.WithClock(clock),
+		rotation.WithRotationPeriod(time.Hour),
+		rotation.WithPrePublication(time.Hour),
+	)
+	require.NoError(t, err)
+
+	entries := m.Entries()
+	require.Len(t, entries, 2)
+	next := entries[1]
+	next.Since = clock.Now().Add(30 * time.Minute)
+	require.NoError(t, storage.Store(ctx, []rotation.Entry{entries[0], next}))
+
+	clock.Advance(time.Hour)
+	require.NoError(t, m.Rotate(ctx))
+	assert.Equal(t, entries[0].Key.ID(), m.SigningKey().ID())
+
+	clock.Advance(30 * time.Minute)
+	require.NoError(t, m.Rotate(ctx))
+	assert.Equal(t, next.Key.ID(), m.SigningKey().ID())
+
+	_, err = rotation.NewManager(ctx, storage,
+		rotation.WithRotationPeriod(time.Hour),
+		rotation.WithPrePublication(2*time.Hour),
+	)
+	assert.ErrorIs(t, err, rotation.ErrInvalidSchedule)
+
+	var runErr error
+	m.Run(ctx, 0, func(err error) { runErr = err })
+	assert.ErrorIs(t, runErr, rotation.ErrInvalidSchedule)
+}
+
+// recordingPublisher records the published key sets, or fails with err if it is set.
+type recordingPublisher struct {
+	sets []*jwk.Set
+	err  error
+}
+
+func (p *recordingPublisher) Store(set *jwk.Set) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.sets = append(p.sets, set)
+	return nil
+}
+
+func (p *recordingPublisher) last() []string {
+	var kids []string
+	for _, k := range p.sets[len(p.sets)-1].Keys() {
+		kids = append(kids, k.ID())
+	}
+	return kids
+}
+
+func TestManagerStorageChanged(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	storage := rotation.NewMemoryStorage()
+	publisher := &recordingPublisher{}
+	m, err := rotation.NewManager(ctx, storage,
+		rotation.WithClock(clock),
+		rotation.WithKeyType(jwk.OKP),
+		rotation.WithPublisher(publisher),
+	)
+	require.NoError(t, err)
+	require.Len(t, publisher.sets, 1)
+
+	// Nothing changed, so nothing is published again
+	require.NoError(t, m.Rotate(ctx))
+	assert.Len(t, publisher.sets, 1)
+
+	// Keys changed in the storage, e.g. restored from a backup, are published
+	entries := m.Entries()
+	next, err := jwk.Generate(jwk.OKP)
+	require.NoError(t, err)
+	require.NoError(t, storage.Store(ctx, []rotation.Entry{
+		{Key: entries[1].Key, Phase: rotation.Current, Since: clock.Now()},
+		{Key: next, Phase: rotation.Next, Since: clock.Now()},
+		{Key: entries[0].Key, Phase: rotation.Retiring, Since: clock.Now()},
+	}))
+	require.NoError(t, m.Rotate(ctx))
+	require.Len(t, publisher.sets, 2)
+	assert.Equal(t, []string{entries[1].Key.ID(), next.ID(), entries[0].Key.ID()}, publisher.last())
+	assert.Equal(t, entries[1].Key.ID(), m.SigningKey().ID())
+}
+
+func TestManagerPublishingFailure(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	publisher := &recordingPublisher{}
+	m, err := rotation.NewManager(ctx, rotation.NewMemoryStorage(),
+		rotation.WithClock(clock),
+		rotation.WithKeyType(jwk.OKP),
+		rotation.WithRotationPeriod(time.Hour),
+		rotation.WithPrePublication(time.Hour),
+		rotation.WithPublisher(publisher),
+	)
+	require.NoError(t, err)
+	initial := phases(m)
+
+	// The rotated keys are stored, but not used until they are published
+	errPublish := errors.New("publish failed")
+	publisher.err = errPublish
+	clock.Advance(time.Hour)
+	assert.ErrorIs(t, m.Rotate(ctx), errPublish)
+	assert.Equal(t, initial, phases(m))
+
+	// The publication is retried, although the stored keys did not change
+	publisher.err = nil
+	require.NoError(t, m.Rotate(ctx))
+	require.Len(t, publisher.sets, 2)
+	rotated := phases(m)
+	assert.Equal(t, initial[rotation.Next], rotated[rotation.Current])
+	assert.Equal(t, []string{rotated[rotation.Current][0], rotated[rotation.Next][0], rotated[rotation.Retiring][0]}, publisher.last())
+}
@@ -0,0 +1,122 @@
+package rotation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/jgraeger/jwgo/jwk"
+)
+
+// MemoryStorage keeps the keys in memory.
+type MemoryStorage struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{}
+}
+
+func (s *MemoryStorage) Load(context.Context) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.entries), nil
+}
+
+func (s *MemoryStorage) Store(_ context.Context, entries []Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = slices.Clone(entries)
+	return nil
+}
+
+// FileStorage keeps the keys in a JSON file, which is only readable by the owner.
+// The file is replaced atomically.
+type FileStorage struct {
+	path string
+}
+
+func NewFileStorage(path string) *FileStorage {
+	return &FileStorage{path: path}
+}
+
+// storedEntry is the JSON representation of an Entry.
+type storedEntry struct {
+	Phase Phase           `json:"phase"`
+	Since time.Time       `json:"since"`
+	Key   json.RawMessage `json:"key"`
+}
+
+func (s *FileStorage) Load(context.Context) ([]Entry, error) {
+	b, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var stored []storedEntry
+	if err := json.Unmarshal(b, &stored); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", s.path, err)
+	}
+
+	entries := make([]Entry, len(stored))
+	for i, e := range stored {
+		k, err := jwk.Parse(e.Key)
+		if err != nil {
+			return nil, fmt.Errorf("decode %s: key %d: %w", s.path, i, err)
+		}
+		entries[i] = Entry{Key: k, Phase: e.Phase, Since: e.Since}
+	}
+	return entries, nil
+}
+
+func (s *FileStorage) Store(_ context.Context, entries []Entry) error {
+	stored := make([]storedEntry, len(entries))
+	for i, e := range entries {
+		k, err := e.Key.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		stored[i] = storedEntry{Phase: e.Phase, Since: e.Since, Key: k}
+	}
+
+	b, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so the keys are never partially written
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.path)
+}
+
+// Interface guards
+var (
+	_ Storage = (*MemoryStorage)(nil)
+	_ Storage = (*FileStorage)(nil)
+)
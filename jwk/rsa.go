@@ -1,9 +1,11 @@
 package jwk
 
 import (
+	"bytes"
 	"crypto"
 	"crypto/rsa"
 	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/jgraeger/jwgo/internal/base64"
@@ -19,6 +21,65 @@ func (k RSAPrivateKey) Thumbprint(hash crypto.Hash) ([]byte, error) {
 	return rsaThumbPrint(hash, k.rsa.PublicKey)
 }
 
+// Raw returns the underlying *rsa.PrivateKey.
+func (k RSAPrivateKey) Raw() any {
+	return k.rsa
+}
+
+func (k RSAPrivateKey) PublicKey() (Key, error) {
+	return &RSAPublicKey{
+		Header: k.Header.public(),
+		rsa:    k.rsa.PublicKey,
+	}, nil
+}
+
+// Public implements crypto.Signer and crypto.Decrypter.
+func (k RSAPrivateKey) Public() crypto.PublicKey {
+	return &k.rsa.PublicKey
+}
+
+// Sign implements crypto.Signer.
+func (k RSAPrivateKey) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if !k.AllowsOperation(KeyOpSign) {
+		return nil, operationNotPermittedErr(KeyOpSign)
+	}
+	return k.rsa.Sign(rand, digest, opts)
+}
+
+// Decrypt implements crypto.Decrypter. It is permitted for keys allowing
+// the decrypt or unwrapKey operation.
+func (k RSAPrivateKey) Decrypt(rand io.Reader, msg []byte, opts crypto.DecrypterOpts) ([]byte, error) {
+	if !k.AllowsOperation(KeyOpDecrypt) && !k.AllowsOperation(KeyOpUnwrapKey) {
+		return nil, operationNotPermittedErr(KeyOpDecrypt)
+	}
+	return k.rsa.Decrypt(rand, msg, opts)
+}
+
+func (k RSAPrivateKey) MarshalJSON() ([]byte, error) {
+	dp, dq, qi, err := rsaCRTValues(k.rsa)
+	if err != nil {
+		return nil, err
+	}
+	others, err := rsaOtherCRTValues(k.rsa)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalKey(k.Header, RSA, func(buf *bytes.Buffer) {
+		writeRSAPublicClaims(buf, k.rsa.PublicKey)
+		writeBase64Member(buf, "d", k.rsa.D.Bytes())
+		writeBase64Member(buf, "p", k.rsa.Primes[0].Bytes())
+		writeBase64Member(buf, "q", k.rsa.Primes[1].Bytes())
+		writeBase64Member(buf, "dp", dp.Bytes())
+		writeBase64Member(buf, "dq", dq.Bytes())
+		writeBase64Member(buf, "qi", qi.Bytes())
+
+		if len(others) > 0 {
+			writeRSAOtherPrimes(buf, k.rsa.Primes[2:], others)
+		}
+	}), nil
+}
+
 type RSAPublicKey struct {
 	Header
 	rsa rsa.PublicKey
@@ -28,10 +89,32 @@ func (k RSAPublicKey) Thumbprint(hash crypto.Hash) ([]byte, error) {
 	return rsaThumbPrint(hash, k.rsa)
 }
 
+// Raw returns the underlying *rsa.PublicKey.
+func (k RSAPublicKey) Raw() any {
+	return &k.rsa
+}
+
+func (k RSAPublicKey) PublicKey() (Key, error) {
+	return &k, nil
+}
+
+// Public returns the underlying *rsa.PublicKey.
+func (k RSAPublicKey) Public() crypto.PublicKey {
+	return &k.rsa
+}
+
+func (k RSAPublicKey) MarshalJSON() ([]byte, error) {
+	return marshalKey(k.Header, RSA, func(buf *bytes.Buffer) {
+		writeRSAPublicClaims(buf, k.rsa)
+	}), nil
+}
+
 // Interface guards
 var (
-	_ Key = (*RSAPrivateKey)(nil)
-	_ Key = (*RSAPublicKey)(nil)
+	_ Key              = (*RSAPrivateKey)(nil)
+	_ Key              = (*RSAPublicKey)(nil)
+	_ crypto.Signer    = (*RSAPrivateKey)(nil)
+	_ crypto.Decrypter = (*RSAPrivateKey)(nil)
 )
 
 func rsaThumbPrint(hash crypto.Hash, key rsa.PublicKey) ([]byte, error) {
@@ -51,13 +134,92 @@ func rsaThumbPrint(hash crypto.Hash, key rsa.PublicKey) ([]byte, error) {
 	return h.Sum(nil), nil
 }
 
-func (pk parsedJWK) toRSAKey() (Key, error) {
+func writeRSAPublicClaims(buf *bytes.Buffer, key rsa.PublicKey) {
+	writeBase64Member(buf, "n", key.N.Bytes())
+	writeStringMember(buf, "e", base64.RawURLEncoding.EncodeUInt64ToString(uint64(key.E)))
+}
+
+// rsaCRTValues returns the CRT exponents and coefficient of the key.
+// They are computed from the private exponent and the primes if the key was not precomputed.
+func rsaCRTValues(key *rsa.PrivateKey) (dp, dq, qi *big.Int, err error) {
+	if key.Precomputed.Dp != nil && key.Precomputed.Dq != nil && key.Precomputed.Qinv != nil {
+		return key.Precomputed.Dp, key.Precomputed.Dq, key.Precomputed.Qinv, nil
+	}
+
+	p, q := key.Primes[0], key.Primes[1]
+	one := big.NewInt(1)
+
+	dp = new(big.Int).Sub(p, one)
+	dp.Mod(key.D, dp)
+
+	dq = new(big.Int).Sub(q, one)
+	dq.Mod(key.D, dq)
+
+	// q has no inverse if the primes are not coprime, e.g. if p == q
+	qi = new(big.Int).ModInverse(q, p)
+	if qi == nil {
+		return nil, nil, nil, fmt.Errorf("%w: rsa: primes are not pairwise coprime", ErrMalformedKey)
+	}
+
+	return dp, dq, qi, nil
+}
+
+// rsaOtherCRTValues returns the CRT values of the third and subsequent primes.
+// They are computed from the private exponent and the primes if the key was not precomputed.
+func rsaOtherCRTValues(key *rsa.PrivateKey) ([]rsa.CRTValue, error) {
+	if len(key.Precomputed.CRTValues) == len(key.Primes)-2 {
+		return key.Precomputed.CRTValues, nil
+	}
+
+	one := big.NewInt(1)
+	r := new(big.Int).Mul(key.Primes[0], key.Primes[1])
+	values := make([]rsa.CRTValue, len(key.Primes)-2)
+	for i, prime := range key.Primes[2:] {
+		coeff := new(big.Int).ModInverse(r, prime)
+		if coeff == nil {
+			return nil, fmt.Errorf("%w: rsa: primes are not pairwise coprime", ErrMalformedKey)
+		}
+
+		exp := new(big.Int).Sub(prime, one)
+		values[i] = rsa.CRTValue{
+			Exp:   exp.Mod(key.D, exp),
+			Coeff: coeff,
+			R:     new(big.Int).Set(r),
+		}
+		r.Mul(r, prime)
+	}
+	return values, nil
+}
+
+// writeRSAOtherPrimes writes the `oth` claim of multi-prime keys (RFC 7518, Section 6.3.2.7).
+func writeRSAOtherPrimes(buf *bytes.Buffer, primes []*big.Int, values []rsa.CRTValue) {
+	buf.WriteString(`,"oth":[`)
+	for i, crt := range values {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(`{"r":"`)
+		writeBase64(buf, primes[i].Bytes())
+		buf.WriteString(`","d":"`)
+		writeBase64(buf, crt.Exp.Bytes())
+		buf.WriteString(`","t":"`)
+		writeBase64(buf, crt.Coeff.Bytes())
+		buf.WriteString(`"}`)
+	}
+	buf.WriteByte(']')
+}
+
+func (pk parsedJWK) toRSAKey(cfg parseConfig) (Key, error) {
 	pub, err := buildRSAPublicKey(pk.k)
 	if err != nil {
 		return nil, err
 	}
 
-	if !hasPrivateKeyClaims(pk.k) {
+	if cfg.maxRSABits > 0 && pub.N.BitLen() > cfg.maxRSABits {
+		return nil, fmt.Errorf("%w: rsa: modulus of %d bits exceeds the maximum of %d bits", ErrMalformedKey, pub.N.BitLen(), cfg.maxRSABits)
+	}
+
+	if !hasPrivateKeyClaims(pk.k) && len(pk.oth) == 0 {
 		return &RSAPublicKey{
 			Header: pk.Header,
 			rsa:    pub,
@@ -65,7 +227,7 @@ func (pk parsedJWK) toRSAKey() (Key, error) {
 	}
 
 	// If we have at least on private key claim, we treat the key as a private key.
-	d, q, p, err := getPrivateKeyParams(pk.k)
+	d, primes, err := getPrivateKeyParams(pk.k, pk.oth)
 	if err != nil {
 		return nil, err
 	}
@@ -73,11 +235,33 @@ func (pk parsedJWK) toRSAKey() (Key, error) {
 	priv := &rsa.PrivateKey{
 		PublicKey: pub,
 		D:         d,
-		Primes:    []*big.Int{p, q},
+		Primes:    primes,
 	}
 
-	// TODO: Make prime precomputation opt-in on the parser API.
-	// priv.Precompute()
+	if !cfg.skipValidation {
+		n := new(big.Int).Set(primes[0])
+		for _, prime := range primes[1:] {
+			n.Mul(n, prime)
+		}
+		if n.Cmp(pub.N) != 0 {
+			return nil, fmt.Errorf("%w: rsa: primes do not match the modulus", ErrMalformedKey)
+		}
+	}
+
+	if cfg.rsaCRTValues {
+		if err := setRSACRTValues(priv, pk.k, pk.oth, cfg.skipValidation); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.rsaPrecompute {
+		if !cfg.skipValidation {
+			if err := priv.Validate(); err != nil {
+				return nil, fmt.Errorf("%w: rsa: %w", ErrMalformedKey, err)
+			}
+		}
+		priv.Precompute()
+	}
 
 	return &RSAPrivateKey{
 		Header: pk.Header,
@@ -104,23 +288,118 @@ func buildRSAPublicKey(claims parsedClaims) (k rsa.PublicKey, err error) {
 	}, nil
 }
 
-func getPrivateKeyParams(claims parsedClaims) (d, q, p *big.Int, err error) {
+// getPrivateKeyParams returns the private exponent and the primes of the key.
+// The first two primes are p and q, followed by the primes of the `oth` claim.
+func getPrivateKeyParams(claims parsedClaims, oth []parsedClaims) (d *big.Int, primes []*big.Int, err error) {
 	d, err = getBigIntClaim(claims, "d")
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, err
+	}
+
+	primes = make([]*big.Int, 2, 2+len(oth))
+	primes[0], err = getBigIntClaim(claims, "p")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	primes[1], err = getBigIntClaim(claims, "q")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, o := range oth {
+		r, err := getBigIntClaim(o, "r")
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s[%d]: %w", ClaimOth, i, err)
+		}
+		primes = append(primes, r)
 	}
 
-	q, err = getBigIntClaim(claims, "q")
+	// The CRT computations divide by prime-1 and invert each prime modulo the product
+	// of the preceding primes, so we reject trivial and repeated factors upfront
+	one := big.NewInt(1)
+	r := new(big.Int)
+	for i, prime := range primes {
+		if prime.Cmp(one) <= 0 {
+			return nil, nil, fmt.Errorf("%w: rsa: prime factor is <= 1", ErrMalformedKey)
+		}
+		if i == 0 {
+			r.Set(prime)
+			continue
+		}
+		if new(big.Int).GCD(nil, nil, r, prime).Cmp(one) != 0 {
+			return nil, nil, fmt.Errorf("%w: rsa: primes are not pairwise coprime", ErrMalformedKey)
+		}
+		r.Mul(r, prime)
+	}
+
+	return d, primes, nil
+}
+
+// setRSACRTValues sets the CRT values of the key from the dp, dq and qi claims, if present.
+// Unless validation is skipped, the values are checked against the primes.
+func setRSACRTValues(priv *rsa.PrivateKey, claims parsedClaims, oth []parsedClaims, skipValidation bool) error {
+	var crt [3]*big.Int
+	for i, c := range []string{"dp", "dq", "qi"} {
+		if _, ok := claims[c]; !ok {
+			return nil
+		}
+
+		v, err := getBigIntClaim(claims, c)
+		if err != nil {
+			return err
+		}
+		crt[i] = v
+	}
+
+	if !skipValidation {
+		dp, dq, qi, err := rsaCRTValues(priv)
+		if err != nil {
+			return err
+		}
+		if crt[0].Cmp(dp) != 0 || crt[1].Cmp(dq) != 0 || crt[2].Cmp(qi) != 0 {
+			return fmt.Errorf("%w: rsa: CRT values do not match the primes", ErrMalformedKey)
+		}
+	}
+
+	others, err := getRSAOtherCRTValues(priv, oth, skipValidation)
 	if err != nil {
-		return nil, nil, nil, err
+		return err
+	}
+
+	priv.Precomputed.Dp, priv.Precomputed.Dq, priv.Precomputed.Qinv = crt[0], crt[1], crt[2]
+	priv.Precomputed.CRTValues = others
+	return nil
+}
+
+// getRSAOtherCRTValues reads the CRT values of the `oth` claim.
+// Unless validation is skipped, the values are checked against the primes.
+func getRSAOtherCRTValues(priv *rsa.PrivateKey, oth []parsedClaims, skipValidation bool) ([]rsa.CRTValue, error) {
+	if len(oth) == 0 {
+		return nil, nil
 	}
 
-	p, err = getBigIntClaim(claims, "p")
+	// The product of the preceding primes (R) is not part of the JWK, so we start with the computed values.
+	values, err := rsaOtherCRTValues(priv)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, err
 	}
+	for i, o := range oth {
+		d, err := getBigIntClaim(o, "d")
+		if err != nil {
+			return nil, fmt.Errorf("%s[%d]: %w", ClaimOth, i, err)
+		}
+		t, err := getBigIntClaim(o, "t")
+		if err != nil {
+			return nil, fmt.Errorf("%s[%d]: %w", ClaimOth, i, err)
+		}
 
-	return d, q, p, nil
+		if !skipValidation && (d.Cmp(values[i].Exp) != 0 || t.Cmp(values[i].Coeff) != 0) {
+			return nil, fmt.Errorf("%w: rsa: CRT values of %s[%d] do not match the primes", ErrMalformedKey, ClaimOth, i)
+		}
+		values[i].Exp, values[i].Coeff = d, t
+	}
+	return values, nil
 }
 
 var rsaPrivateKeyClaims = []string{"p", "q", "d", "qi", "dp", "dq"}
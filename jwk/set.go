@@ -0,0 +1,203 @@
+package jwk
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/jgraeger/jwgo/internal/pool"
+	"github.com/jgraeger/jwgo/jwa"
+)
+
+const (
+	ClaimKeys = "keys"
+)
+
+// Set is a JWK Set as defined in RFC 7517, Section 5.
+type Set struct {
+	keys []Key
+}
+
+// NewSet creates a set of the keys.
+func NewSet(keys ...Key) *Set {
+	return &Set{keys: slices.Clone(keys)}
+}
+
+func ParseSet(set []byte, opts ...ParseOption) (*Set, error) {
+	return ParseSetReader(bytes.NewReader(set), opts...)
+}
+
+func ParseSetString(set string, opts ...ParseOption) (*Set, error) {
+	return ParseSetReader(strings.NewReader(set), opts...)
+}
+
+func ParseSetReader(r io.Reader, opts ...ParseOption) (*Set, error) {
+	cfg := newParseConfig(opts)
+
+	r, err := cfg.limitInput(r)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(r)
+
+	// Read opening brace
+	if _, err := readNextJSONDelimiter(dec); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedJSON, err)
+	}
+
+	s := &Set{}
+	for dec.More() {
+		member, err := nextStringToken(dec)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrMalformedJSON, err)
+		}
+
+		if member != ClaimKeys {
+			// Members other than `keys` are not used, so we skip their values.
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrMalformedJSON, err)
+			}
+			continue
+		}
+
+		if err := s.parseKeys(dec, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	// Read closing brace
+	if _, err := readNextJSONDelimiter(dec); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedJSON, err)
+	}
+
+	return s, nil
+}
+
+func (s *Set) parseKeys(dec *json.Decoder, cfg parseConfig) error {
+	if d, err := readNextJSONDelimiter(dec); err != nil {
+		return fmt.Errorf("%w: %w", ErrMalformedJSON, err)
+	} else if d != '[' {
+		return fmt.Errorf("%w: expected array of keys", ErrMalformedJSON)
+	}
+
+	for i := 0; dec.More(); i++ {
+		k, err := parseKey(dec, cfg)
+		switch {
+		case err == nil:
+			s.keys = append(s.keys, k)
+		case !cfg.rejectUnsupported && isUnsupportedKeyErr(err):
+			continue
+		default:
+			return fmt.Errorf("key %d: %w", i, err)
+		}
+	}
+
+	// Read closing bracket
+	if _, err := readNextJSONDelimiter(dec); err != nil {
+		return fmt.Errorf("%w: %w", ErrMalformedJSON, err)
+	}
+
+	return nil
+}
+
+// isUnsupportedKeyErr reports whether the key was rejected because this package
+// does not support it, as opposed to the key being malformed.
+func isUnsupportedKeyErr(err error) bool {
+	return errors.Is(err, ErrUnknownType) ||
+		errors.Is(err, ErrUnknownCurve) ||
+		errors.Is(err, jwa.ErrUnknownAlg)
+}
+
+func (s *Set) MarshalJSON() ([]byte, error) {
+	buf := pool.GetBytesBuffer()
+	defer pool.PutBytesBuffer(buf)
+
+	buf.WriteString(`{"keys":[`)
+	for i, k := range s.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		b, err := k.MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("key %d: %w", i, err)
+		}
+		buf.Write(b)
+	}
+	buf.WriteString(`]}`)
+
+	return bytes.Clone(buf.Bytes()), nil
+}
+
+// PublicKeys returns a set of the public keys of all keys in the set.
+// Symmetric keys are left out, as they have no public key.
+func (s *Set) PublicKeys() (*Set, error) {
+	pub := &Set{keys: make([]Key, 0, len(s.keys))}
+	for i, k := range s.keys {
+		pk, err := k.PublicKey()
+		if errors.Is(err, ErrNoPublicKey) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("key %d: %w", i, err)
+		}
+		pub.keys = append(pub.keys, pk)
+	}
+	return pub, nil
+}
+
+// Len returns the number of keys in the set.
+func (s *Set) Len() int {
+	return len(s.keys)
+}
+
+// Keys returns a copy of the keys in the set.
+func (s *Set) Keys() []Key {
+	keys := make([]Key, len(s.keys))
+	copy(keys, s.keys)
+	return keys
+}
+
+// Range calls fn for each key in the set until fn returns false.
+func (s *Set) Range(fn func(i int, k Key) bool) {
+	for i, k := range s.keys {
+		if !fn(i, k) {
+			return
+		}
+	}
+}
+
+// LookupKeyID returns the first key with the given `kid`.
+func (s *Set) LookupKeyID(kid string) (Key, bool) {
+	for _, k := range s.keys {
+		if k.ID() == kid {
+			return k, true
+		}
+	}
+	return nil, false
+}
+
+// LookupKeyIDAlg returns the first key with the given `kid` and `alg`.
+func (s *Set) LookupKeyIDAlg(kid string, alg jwa.KeyAlgorithm) (Key, bool) {
+	for _, k := range s.keys {
+		if k.ID() == kid && k.Algorithm() == alg {
+			return k, true
+		}
+	}
+	return nil, false
+}
+
+// LookupUsage returns all keys with the given `use`.
+func (s *Set) LookupUsage(use KeyUsage) []Key {
+	var keys []Key
+	for _, k := range s.keys {
+		if k.Usage() == use {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
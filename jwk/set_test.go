@@ -0,0 +1,121 @@
+package jwk_test
+
+import (
+	"testing"
+
+	"github.com/jgraeger/jwgo/jwa"
+	"github.com/jgraeger/jwgo/jwk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSetJSON = `{
+	"keys": [
+		{
+			"kty": "EC",
+			"alg": "ES256",
+			"kid": "ec-1",
+			"use": "sig",
+			"crv": "P-256",
+			"x": "PBS-U2F4tvM0c9G9AlFwsq_-D-avJWC6vVm-VoeGzy4",
+			"y": "_nK1STaH_B6ySZR_4waibSjUuxdulspHHzMIbgsoExE"
+		},
+		{
+			"kty": "UNKNOWN",
+			"alg": "RS256",
+			"kid": "unknown-1"
+		},
+		{
+			"kty": "OKP",
+			"alg": "EdDSA",
+			"kid": "ed-1",
+			"use": "sig",
+			"crv": "Ed25519",
+			"x": "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"
+		},
+		{
+			"kty": "oct",
+			"alg": "HS256",
+			"kid": "ec-1",
+			"use": "enc",
+			"k": "c3VwZXJzZWNyZXRzdXBlcnNlY3JldHN1cGVyc2VjcmV0ISE"
+		}
+	],
+	"extra": {"ignored": [1, 2, 3]}
+}`
+
+func TestParseSet(t *testing.T) {
+	t.Parallel()
+
+	t.Run("skips unsupported keys by default", func(t *testing.T) {
+		t.Parallel()
+
+		set, err := jwk.ParseSetString(testSetJSON)
+		require.NoError(t, err)
+		require.Equal(t, 3, set.Len())
+
+		var ids []string
+		set.Range(func(_ int, k jwk.Key) bool {
+			ids = append(ids, k.ID())
+			return true
+		})
+		assert.Equal(t, []string{"ec-1", "ed-1", "ec-1"}, ids)
+	})
+
+	t.Run("rejects unsupported keys", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := jwk.ParseSetString(testSetJSON, jwk.WithRejectUnsupportedKeys())
+		assert.ErrorIs(t, err, jwk.ErrUnknownType)
+	})
+
+	t.Run("malformed keys are not skipped", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := jwk.ParseSetString(`{"keys": [{"kty": "EC", "alg": "ES256", "crv": "P-256", "x": "AQAB"}]}`)
+		assert.ErrorIs(t, err, jwk.ErrMalformedKey)
+	})
+
+	t.Run("malformed JSON", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := jwk.ParseSetString(`{"keys": {}}`)
+		assert.ErrorIs(t, err, jwk.ErrMalformedJSON)
+	})
+}
+
+func TestSetLookup(t *testing.T) {
+	t.Parallel()
+
+	set, err := jwk.ParseSetString(testSetJSON)
+	require.NoError(t, err)
+
+	t.Run("LookupKeyID", func(t *testing.T) {
+		t.Parallel()
+
+		k, ok := set.LookupKeyID("ed-1")
+		require.True(t, ok)
+		assert.Equal(t, jwk.OKP, k.Type())
+
+		_, ok = set.LookupKeyID("unknown-1")
+		assert.False(t, ok)
+	})
+
+	t.Run("LookupKeyIDAlg", func(t *testing.T) {
+		t.Parallel()
+
+		k, ok := set.LookupKeyIDAlg("ec-1", jwa.KeyAlgorithmMustFrom(jwa.HS256))
+		require.True(t, ok)
+		assert.Equal(t, jwk.Oct, k.Type())
+
+		_, ok = set.LookupKeyIDAlg("ed-1", jwa.KeyAlgorithmMustFrom(jwa.ES256))
+		assert.False(t, ok)
+	})
+
+	t.Run("LookupUsage", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Len(t, set.LookupUsage(jwk.Signing), 2)
+		assert.Len(t, set.LookupUsage(jwk.Encryption), 1)
+	})
+}
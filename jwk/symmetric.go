@@ -0,0 +1,90 @@
+package jwk
+
+import (
+	"bytes"
+	"crypto"
+	"fmt"
+
+	"github.com/jgraeger/jwgo/internal/base64"
+	"github.com/jgraeger/jwgo/internal/pool"
+	"github.com/jgraeger/jwgo/jwa"
+)
+
+// SymmetricKey is an octet sequence key (`oct`), used for HMAC and AES.
+type SymmetricKey struct {
+	Header
+	k []byte
+}
+
+func (k SymmetricKey) Thumbprint(hash crypto.Hash) ([]byte, error) {
+	buf := pool.GetBytesBuffer()
+	defer pool.PutBytesBuffer(buf)
+
+	buf.WriteString(`{"k":"`)
+	buf.WriteString(base64.RawURLEncoding.EncodeToString(k.k))
+	buf.WriteString(`","kty":"oct"}`)
+
+	h := hash.New()
+	if _, err := buf.WriteTo(h); err != nil {
+		return nil, fmt.Errorf("write thumbprint to hash: %w", err)
+	}
+	return h.Sum(nil), nil
+}
+
+func (k SymmetricKey) MarshalJSON() ([]byte, error) {
+	return marshalKey(k.Header, Oct, func(buf *bytes.Buffer) {
+		writeBase64Member(buf, "k", k.k)
+	}), nil
+}
+
+// Raw returns a copy of the key bytes.
+func (k SymmetricKey) Raw() any {
+	return append([]byte(nil), k.k...)
+}
+
+// PublicKey always fails, as symmetric keys have no public part.
+func (k SymmetricKey) PublicKey() (Key, error) {
+	return nil, fmt.Errorf("%w: %s", ErrNoPublicKey, Oct)
+}
+
+// Format implements fmt.Formatter, so the secret never ends up in logs.
+func (k SymmetricKey) Format(f fmt.State, _ rune) {
+	fmt.Fprintf(f, "{Header:%+v k:[REDACTED]}", k.Header)
+}
+
+// Interface guards
+var (
+	_ Key           = (*SymmetricKey)(nil)
+	_ fmt.Formatter = (*SymmetricKey)(nil)
+)
+
+// minSymmetricKeySize returns the minimum key size in bytes for the algorithm.
+// RFC 7518, Section 3.2 requires HMAC keys to be at least as long as the hash output.
+func minSymmetricKeySize(alg jwa.KeyAlgorithm) int {
+	switch alg {
+	case jwa.KeyAlgorithm(jwa.HS256):
+		return 32
+	case jwa.KeyAlgorithm(jwa.HS384):
+		return 48
+	case jwa.KeyAlgorithm(jwa.HS512):
+		return 64
+	default:
+		return 1
+	}
+}
+
+func (pk parsedJWK) toSymmetricKey() (Key, error) {
+	k, err := getBytesClaim(pk.k, "k")
+	if err != nil {
+		return nil, err
+	}
+
+	if minSize := minSymmetricKeySize(pk.Alg); len(k) < minSize {
+		return nil, fmt.Errorf("%w: oct: key of %d bytes is too short for %s", ErrMalformedKey, len(k), pk.Alg)
+	}
+
+	return &SymmetricKey{
+		Header: pk.Header,
+		k:      k,
+	}, nil
+}
@@ -0,0 +1,255 @@
+package jwk
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha1" //nolint:gosec // required for x5t
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jgraeger/jwgo/internal/base64"
+)
+
+func (h Header) X509CertChain() []*x509.Certificate {
+	return h.X5C
+}
+
+func (h Header) X509Thumbprint() []byte {
+	return h.X5T
+}
+
+func (h Header) X509ThumbprintS256() []byte {
+	return h.X5TS256
+}
+
+func (h Header) X509URL() string {
+	return h.X5U
+}
+
+// toCertChain converts the decoded `x5c` array. Unlike other binary claims,
+// the certificates are encoded using standard base64 (RFC 7517, Section 4.7).
+func toCertChain(v any) ([]*x509.Certificate, error) {
+	arr, ok := v.([]any)
+	if !ok || len(arr) == 0 {
+		return nil, fmt.Errorf("%w: claim `%s` must be a non-empty array", ErrMalformedJSON, ClaimX5c)
+	}
+
+	certs := make([]*x509.Certificate, len(arr))
+	for i, c := range arr {
+		s, ok := c.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: claim `%s` must contain strings", ErrMalformedJSON, ClaimX5c)
+		}
+
+		der, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to decode %s[%d]: %w", ErrMalformedKey, ClaimX5c, i, err)
+		}
+
+		certs[i], err = x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to parse %s[%d]: %w", ErrMalformedKey, ClaimX5c, i, err)
+		}
+	}
+	return certs, nil
+}
+
+func decodeThumbprint(claim, s string, size int) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decode %s claim: %w", ErrMalformedKey, claim, err)
+	} else if len(b) != size {
+		return nil, fmt.Errorf("%w: claim `%s` must be %d bytes", ErrMalformedKey, claim, size)
+	}
+	return b, nil
+}
+
+// validateX509 checks that the leaf certificate of the `x5c` claim contains
+// the public key and matches the `x5t` and `x5t#S256` thumbprints.
+func validateX509(k Key) error {
+	certs := k.X509CertChain()
+	if len(certs) == 0 {
+		return nil
+	}
+	leaf := certs[0]
+
+	pub, err := PublicKeyOf(k)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCertificateMismatch, err)
+	}
+
+	if !leafContainsKey(leaf, pub) {
+		return fmt.Errorf("%w: public key of leaf certificate differs", ErrCertificateMismatch)
+	}
+
+	if x5t := k.X509Thumbprint(); x5t != nil {
+		//nolint:gosec // required for x5t
+		if sum := sha1.Sum(leaf.Raw); !bytes.Equal(sum[:], x5t) {
+			return fmt.Errorf("%w: %s does not match leaf certificate", ErrCertificateMismatch, ClaimX5t)
+		}
+	}
+
+	if x5t := k.X509ThumbprintS256(); x5t != nil {
+		if sum := sha256.Sum256(leaf.Raw); !bytes.Equal(sum[:], x5t) {
+			return fmt.Errorf("%w: %s does not match leaf certificate", ErrCertificateMismatch, ClaimX5tS256)
+		}
+	}
+
+	return nil
+}
+
+// VerifyCertChain builds a certificate chain from the `x5c` claim of k to one
+// of the roots and validates it. The leaf certificate must contain the public key of k.
+// Certificates that fail verification are reported as *CertificateError.
+// The roots must not be nil, use x509.SystemCertPool to trust the system roots.
+func VerifyCertChain(k Key, roots *x509.CertPool, opts ...VerifyOption) error {
+	cfg := newVerifyConfig(opts)
+
+	// x509 would fall back to the system roots, which is too broad to pin a chain
+	if roots == nil {
+		return fmt.Errorf("%w: no trust roots", ErrInvalidCertChain)
+	}
+
+	certs := k.X509CertChain()
+	if len(certs) == 0 {
+		return fmt.Errorf("%w: missing %s claim", ErrInvalidCertChain, ClaimX5c)
+	}
+	if err := validateX509(k); err != nil {
+		return &CertificateError{Index: 0, Certificate: certs[0], Reason: err}
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	// x509 defaults to server authentication, which is rarely the purpose of a signing key
+	keyUsages := cfg.keyUsages
+	if len(keyUsages) == 0 {
+		keyUsages = []x509.ExtKeyUsage{x509.ExtKeyUsageAny}
+	}
+
+	chains, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   cfg.now,
+		KeyUsages:     keyUsages,
+		DNSName:       cfg.dnsName,
+	})
+	if err != nil {
+		return certificateErr(certs, err)
+	}
+
+	if len(cfg.permittedNames) > 0 {
+		return checkPermittedDNSNames(certs, chains, cfg.permittedNames)
+	}
+	return nil
+}
+
+// checkPermittedDNSNames checks that one of the verified chains only contains
+// permitted DNS names. The leaf certificate must have at least one DNS name.
+func checkPermittedDNSNames(certs []*x509.Certificate, chains [][]*x509.Certificate, domains []string) error {
+	if len(certs[0].DNSNames) == 0 {
+		return &CertificateError{Index: 0, Certificate: certs[0], Reason: errors.New("leaf certificate has no DNS names")}
+	}
+
+	var first error
+	for _, chain := range chains {
+		err := checkChainDNSNames(certs, chain, domains)
+		if err == nil {
+			return nil
+		} else if first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+func checkChainDNSNames(certs, chain []*x509.Certificate, domains []string) error {
+	for _, cert := range chain {
+		for _, name := range cert.DNSNames {
+			if !permittedDNSName(name, domains) {
+				return &CertificateError{
+					Index:       slices.IndexFunc(certs, cert.Equal),
+					Certificate: cert,
+					Reason:      fmt.Errorf("DNS name %q is not permitted", name),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// certificateErr determines the certificate that caused a verification error.
+func certificateErr(certs []*x509.Certificate, err error) error {
+	// Errors that do not reference a certificate are caused by the leaf
+	cert := certs[0]
+
+	var (
+		invalidErr   x509.CertificateInvalidError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+	)
+	switch {
+	case errors.As(err, &invalidErr) && invalidErr.Cert != nil:
+		cert = invalidErr.Cert
+	case errors.As(err, &authorityErr) && authorityErr.Cert != nil:
+		cert = authorityErr.Cert
+	case errors.As(err, &hostnameErr) && hostnameErr.Certificate != nil:
+		cert = hostnameErr.Certificate
+	}
+
+	index := -1
+	for i, c := range certs {
+		if c.Equal(cert) {
+			index = i
+			break
+		}
+	}
+	return &CertificateError{Index: index, Certificate: cert, Reason: err}
+}
+
+func permittedDNSName(name string, domains []string) bool {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+		if name == domain || strings.HasSuffix(name, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func leafContainsKey(leaf *x509.Certificate, pub crypto.PublicKey) bool {
+	leafPub, ok := leaf.PublicKey.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && leafPub.Equal(pub)
+}
+
+// writeX509JSON writes the X.509 claims as members of an open JSON object into buf.
+func (h Header) writeX509JSON(buf *bytes.Buffer) {
+	if len(h.X5C) > 0 {
+		buf.WriteString(`,"x5c":[`)
+		for i, cert := range h.X5C {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('"')
+			buf.WriteString(base64.StdEncoding.EncodeToString(cert.Raw))
+			buf.WriteByte('"')
+		}
+		buf.WriteByte(']')
+	}
+	if h.X5T != nil {
+		writeBase64Member(buf, ClaimX5t, h.X5T)
+	}
+	if h.X5TS256 != nil {
+		writeBase64Member(buf, ClaimX5tS256, h.X5TS256)
+	}
+	if h.X5U != "" {
+		writeStringMember(buf, ClaimX5u, h.X5U)
+	}
+}
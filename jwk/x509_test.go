@@ -0,0 +1,333 @@
+package jwk_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // required for x5t
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/jgraeger/jwgo/jwa"
+	"github.com/jgraeger/jwgo/jwk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCertificate(t *testing.T, key crypto.Signer) *x509.Certificate {
+	t.Helper()
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "jwgo test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	return signTestCertificate(t, tmpl, key.Public(), tmpl, key)
+}
+
+func signTestCertificate(t *testing.T, tmpl *x509.Certificate, pub crypto.PublicKey, parent *x509.Certificate, parentKey crypto.Signer) *x509.Certificate {
+	t.Helper()
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, pub, parentKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestParseX509(t *testing.T) {
+	t.Parallel()
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	cert := newTestCertificate(t, priv)
+	otherCert := newTestCertificate(t, other)
+
+	key, err := jwk.FromCrypto(&priv.PublicKey, jwk.WithAlgorithm(jwa.KeyAlgorithmMustFrom(jwa.ES256)))
+	require.NoError(t, err)
+	keyJSON, err := json.Marshal(key)
+	require.NoError(t, err)
+
+	x5c := func(certs ...*x509.Certificate) string {
+		chain := make([]string, len(certs))
+		for i, c := range certs {
+			chain[i] = base64.StdEncoding.EncodeToString(c.Raw)
+		}
+		b, _ := json.Marshal(chain)
+		return fmt.Sprintf(`"x5c":%s`, b)
+	}
+	sha1Sum := sha1.Sum(cert.Raw) //nolint:gosec // required for x5t
+	x5t := base64.RawURLEncoding.EncodeToString(sha1Sum[:])
+	sha256Sum := sha256.Sum256(cert.Raw)
+	x5tS256 := base64.RawURLEncoding.EncodeToString(sha256Sum[:])
+
+	for _, tt := range []struct {
+		name        string
+		claims      string
+		expectedErr error
+	}{
+		{
+			name:   "certificate chain with thumbprints",
+			claims: fmt.Sprintf(`%s,"x5t":%q,"x5t#S256":%q,"x5u":"https://example.com/chain.pem"`, x5c(cert, otherCert), x5t, x5tS256),
+		},
+		{
+			name:        "leaf certificate of other key",
+			claims:      x5c(otherCert, cert),
+			expectedErr: jwk.ErrCertificateMismatch,
+		},
+		{
+			name:        "x5t of other certificate",
+			claims:      fmt.Sprintf(`%s,"x5t":%q`, x5c(otherCert), x5t),
+			expectedErr: jwk.ErrCertificateMismatch,
+		},
+		{
+			name:        "x5t#S256 of invalid size",
+			claims:      fmt.Sprintf(`%s,"x5t#S256":%q`, x5c(cert), x5t),
+			expectedErr: jwk.ErrMalformedKey,
+		},
+		{
+			name:        "invalid certificate",
+			claims:      `"x5c":["bm90IGEgY2VydGlmaWNhdGU="]`,
+			expectedErr: jwk.ErrMalformedKey,
+		},
+		{
+			name:        "x5c is not an array",
+			claims:      `"x5c":"bm90IGEgY2VydGlmaWNhdGU="`,
+			expectedErr: jwk.ErrMalformedJSON,
+		},
+	} {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			withClaims := fmt.Sprintf("%s,%s}", keyJSON[:len(keyJSON)-1], tc.claims)
+			parsed, err := jwk.ParseString(withClaims)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Len(t, parsed.X509CertChain(), 2)
+			assert.True(t, parsed.X509CertChain()[0].Equal(cert))
+			assert.Equal(t, sha1Sum[:], parsed.X509Thumbprint())
+			assert.Equal(t, sha256Sum[:], parsed.X509ThumbprintS256())
+			assert.Equal(t, "https://example.com/chain.pem", parsed.X509URL())
+
+			// Round trip
+			b, err := json.Marshal(parsed)
+			require.NoError(t, err)
+			reparsed, err := jwk.Parse(b)
+			require.NoError(t, err)
+			assert.Equal(t, parsed, reparsed)
+		})
+	}
+}
+
+func TestWithX509CertChain(t *testing.T) {
+	t.Parallel()
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	cert := newTestCertificate(t, priv)
+
+	key, err := jwk.FromCrypto(priv, jwk.WithX509CertChain(cert))
+	require.NoError(t, err)
+	assert.Equal(t, []*x509.Certificate{cert}, key.X509CertChain())
+
+	pub, err := key.PublicKey()
+	require.NoError(t, err)
+	assert.Equal(t, key.X509CertChain(), pub.X509CertChain())
+
+	_, err = jwk.FromCrypto(other, jwk.WithX509CertChain(cert))
+	assert.ErrorIs(t, err, jwk.ErrCertificateMismatch)
+
+	_, err = jwk.FromCrypto([]byte("supersecret"), jwk.WithX509CertChain(cert))
+	assert.ErrorIs(t, err, jwk.ErrCertificateMismatch)
+}
+
+func TestVerifyCertChain(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	newKey := func() *ecdsa.PrivateKey {
+		k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		return k
+	}
+	caTemplate := func(cn string, notAfter time.Time) *x509.Certificate {
+		return &x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			Subject:               pkix.Name{CommonName: cn},
+			NotBefore:             now.AddDate(-1, 0, 0),
+			NotAfter:              notAfter,
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign,
+		}
+	}
+
+	rootKey, intermediateKey, leafKey := newKey(), newKey(), newKey()
+	root := signTestCertificate(t, caTemplate("root", now.AddDate(10, 0, 0)), rootKey.Public(), caTemplate("root", now.AddDate(10, 0, 0)), rootKey)
+	intermediate := signTestCertificate(t, caTemplate("intermediate", now.AddDate(1, 0, 0)), intermediateKey.Public(), root, rootKey)
+	expiredIntermediate := signTestCertificate(t, caTemplate("intermediate", now.AddDate(0, 0, -1)), intermediateKey.Public(), root, rootKey)
+	leafTemplate := func(dnsNames ...string) *x509.Certificate {
+		return &x509.Certificate{
+			SerialNumber: big.NewInt(2),
+			Subject:      pkix.Name{CommonName: "leaf"},
+			DNSNames:     dnsNames,
+			NotBefore:    now.AddDate(0, -1, 0),
+			NotAfter:     now.AddDate(0, 1, 0),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		}
+	}
+	leaf := signTestCertificate(t, leafTemplate("signer.example.com"), leafKey.Public(), intermediate, intermediateKey)
+	leafWithoutDNSNames := signTestCertificate(t, leafTemplate(), leafKey.Public(), intermediate, intermediateKey)
+
+	namedTemplate := caTemplate("named intermediate", now.AddDate(1, 0, 0))
+	namedTemplate.DNSNames = []string{"ca.example.org"}
+	namedIntermediate := signTestCertificate(t, namedTemplate, intermediateKey.Public(), root, rootKey)
+	leafOfNamed := signTestCertificate(t, leafTemplate("signer.example.com"), leafKey.Public(), namedIntermediate, intermediateKey)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+
+	// The system roots are not trusted implicitly
+	key, err := jwk.FromCrypto(leafKey, jwk.WithX509CertChain(leaf, intermediate))
+	require.NoError(t, err)
+	assert.ErrorIs(t, jwk.VerifyCertChain(key, nil, jwk.WithVerifyTime(now)), jwk.ErrInvalidCertChain)
+
+	for _, tt := range []struct {
+		name          string
+		chain         []*x509.Certificate
+		roots         *x509.CertPool
+		opts          []jwk.VerifyOption
+		expectedIndex int
+		expectedErr   error
+	}{
+		{
+			name:  "valid chain",
+			chain: []*x509.Certificate{leaf, intermediate},
+			opts: []jwk.VerifyOption{
+				jwk.WithExtKeyUsages(x509.ExtKeyUsageCodeSigning),
+				jwk.WithDNSName("signer.example.com"),
+				jwk.WithPermittedDNSDomains("example.com"),
+			},
+		},
+		{
+			name:          "untrusted root",
+			chain:         []*x509.Certificate{leaf, intermediate},
+			roots:         x509.NewCertPool(),
+			expectedIndex: 1,
+			expectedErr:   jwk.ErrInvalidCertChain,
+		},
+		{
+			name:          "missing intermediate",
+			chain:         []*x509.Certificate{leaf},
+			expectedIndex: 0,
+			expectedErr:   jwk.ErrInvalidCertChain,
+		},
+		{
+			name:          "expired intermediate",
+			chain:         []*x509.Certificate{leaf, expiredIntermediate},
+			expectedIndex: 1,
+			expectedErr:   jwk.ErrInvalidCertChain,
+		},
+		{
+			name:          "expired leaf",
+			chain:         []*x509.Certificate{leaf, intermediate},
+			opts:          []jwk.VerifyOption{jwk.WithVerifyTime(now.AddDate(0, 2, 0))},
+			expectedIndex: 0,
+			expectedErr:   jwk.ErrInvalidCertChain,
+		},
+		{
+			name:          "missing extended key usage",
+			chain:         []*x509.Certificate{leaf, intermediate},
+			opts:          []jwk.VerifyOption{jwk.WithExtKeyUsages(x509.ExtKeyUsageServerAuth)},
+			expectedIndex: 0,
+			expectedErr:   jwk.ErrInvalidCertChain,
+		},
+		{
+			name:          "DNS name mismatch",
+			chain:         []*x509.Certificate{leaf, intermediate},
+			opts:          []jwk.VerifyOption{jwk.WithDNSName("other.example.com")},
+			expectedIndex: 0,
+			expectedErr:   jwk.ErrInvalidCertChain,
+		},
+		{
+			name:          "DNS name not permitted",
+			chain:         []*x509.Certificate{leaf, intermediate},
+			opts:          []jwk.VerifyOption{jwk.WithPermittedDNSDomains("example.org")},
+			expectedIndex: 0,
+			expectedErr:   jwk.ErrInvalidCertChain,
+		},
+		{
+			name:          "leaf without DNS names",
+			chain:         []*x509.Certificate{leafWithoutDNSNames, intermediate},
+			opts:          []jwk.VerifyOption{jwk.WithPermittedDNSDomains("example.com")},
+			expectedIndex: 0,
+			expectedErr:   jwk.ErrInvalidCertChain,
+		},
+		{
+			name:          "DNS name of intermediate not permitted",
+			chain:         []*x509.Certificate{leafOfNamed, namedIntermediate},
+			opts:          []jwk.VerifyOption{jwk.WithPermittedDNSDomains("example.com")},
+			expectedIndex: 1,
+			expectedErr:   jwk.ErrInvalidCertChain,
+		},
+		{
+			name:          "no certificate chain",
+			expectedIndex: -1,
+			expectedErr:   jwk.ErrInvalidCertChain,
+		},
+	} {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			key, err := jwk.FromCrypto(leafKey, jwk.WithX509CertChain(tc.chain...))
+			require.NoError(t, err)
+
+			pool := roots
+			if tc.roots != nil {
+				pool = tc.roots
+			}
+			opts := append([]jwk.VerifyOption{jwk.WithVerifyTime(now)}, tc.opts...)
+
+			err = jwk.VerifyCertChain(key, pool, opts...)
+			if tc.expectedErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.expectedErr)
+
+			var certErr *jwk.CertificateError
+			if tc.expectedIndex < 0 {
+				assert.False(t, errors.As(err, &certErr))
+				return
+			}
+			require.ErrorAs(t, err, &certErr)
+			assert.Equal(t, tc.expectedIndex, certErr.Index)
+			assert.True(t, tc.chain[tc.expectedIndex].Equal(certErr.Certificate))
+		})
+	}
+}
@@ -0,0 +1,386 @@
+package jws
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha256" // register hash functions
+	_ "crypto/sha512"
+	stdbase64 "encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"slices"
+
+	"github.com/jgraeger/jwgo/internal/pool"
+	"github.com/jgraeger/jwgo/jwa"
+	"github.com/jgraeger/jwgo/jwk"
+)
+
+// minRSAKeyBits is the minimum size of RSA keys (RFC 7518, Section 3.3).
+const minRSAKeyBits = 2048
+
+// pssOptions sets the salt length to the size of the hash (RFC 7518, Section 3.5).
+var pssOptions = rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}
+
+type family int
+
+const (
+	familyHMAC family = iota
+	familyRSAPKCS1
+	familyRSAPSS
+	familyECDSA
+	familyEdDSA
+)
+
+// algorithm signs and verifies using a signature algorithm.
+type algorithm struct {
+	alg    jwa.SignatureAlgorithm
+	family family
+	hash   crypto.Hash
+	// crv is the curve of ECDSA algorithms
+	crv jwk.Curve
+}
+
+// ES256K is not supported, as the standard library does not implement secp256k1.
+var algorithms = map[jwa.SignatureAlgorithm]algorithm{
+	jwa.HS256: {alg: jwa.HS256, family: familyHMAC, hash: crypto.SHA256},
+	jwa.HS384: {alg: jwa.HS384, family: familyHMAC, hash: crypto.SHA384},
+	jwa.HS512: {alg: jwa.HS512, family: familyHMAC, hash: crypto.SHA512},
+	jwa.RS256: {alg: jwa.RS256, family: familyRSAPKCS1, hash: crypto.SHA256},
+	jwa.RS384: {alg: jwa.RS384, family: familyRSAPKCS1, hash: crypto.SHA384},
+	jwa.RS512: {alg: jwa.RS512, family: familyRSAPKCS1, hash: crypto.SHA512},
+	jwa.PS256: {alg: jwa.PS256, family: familyRSAPSS, hash: crypto.SHA256},
+	jwa.PS384: {alg: jwa.PS384, family: familyRSAPSS, hash: crypto.SHA384},
+	jwa.PS512: {alg: jwa.PS512, family: familyRSAPSS, hash: crypto.SHA512},
+	jwa.ES256: {alg: jwa.ES256, family: familyECDSA, hash: crypto.SHA256, crv: jwk.P256},
+	jwa.ES384: {alg: jwa.ES384, family: familyECDSA, hash: crypto.SHA384, crv: jwk.P384},
+	jwa.ES512: {alg: jwa.ES512, family: familyECDSA, hash: crypto.SHA512, crv: jwk.P521},
+	jwa.EdDSA: {alg: jwa.EdDSA, family: familyEdDSA},
+}
+
+func lookupAlgorithm(alg jwa.SignatureAlgorithm) (algorithm, error) {
+	a, ok := algorithms[alg]
+	if !ok {
+		return algorithm{}, unsupportedAlgorithmErr(alg)
+	}
+	return a, nil
+}
+
+// checkKey checks that the key may be used with the algorithm for the operation.
+func (a algorithm) checkKey(k jwk.Key, op jwk.KeyOperation) error {
+	if alg := k.Algorithm(); alg != "" && alg.String() != a.alg.String() {
+		return invalidKeyErr(a.alg, k, "key is restricted to "+alg.String())
+	}
+	if !k.AllowsOperation(op) {
+		return fmt.Errorf("%w: %s", jwk.ErrOperationNotPermitted, op)
+	}
+	return nil
+}
+
+// newHash returns the hash of the signing input, or its MAC for HMAC algorithms.
+// It returns nil for EdDSA, which signs the input itself.
+func (a algorithm) newHash(material any) hash.Hash {
+	switch {
+	case a.family == familyHMAC:
+		//nolint:forcetypeassert
+		return hmac.New(a.hash.New, material.([]byte))
+	case a.hash != 0:
+		return a.hash.New()
+	default:
+		return nil
+	}
+}
+
+// digest returns the digest of the input passed to signDigest and verifyDigest.
+func (a algorithm) digest(material any, input []byte) []byte {
+	h := a.newHash(material)
+	if h == nil {
+		return nil
+	}
+	h.Write(input)
+	return h.Sum(nil)
+}
+
+// digestReader computes the digest of the signing input consisting of the prefix
+// and the payload read from r. The payload is base64url encoded if encode is set.
+// The input is only buffered and returned for EdDSA, which cannot sign a digest.
+func (a algorithm) digestReader(material any, prefix []byte, r io.Reader, encode bool) (input, digest []byte, err error) {
+	var buf *bytes.Buffer
+	var w io.Writer
+	h := a.newHash(material)
+	if h != nil {
+		w = h
+	} else {
+		buf = pool.GetBytesBuffer()
+		defer pool.PutBytesBuffer(buf)
+		w = buf
+	}
+
+	if _, err := w.Write(prefix); err != nil {
+		return nil, nil, err
+	}
+	if err := copyPayload(w, r, encode); err != nil {
+		return nil, nil, err
+	}
+
+	if h == nil {
+		return bytes.Clone(buf.Bytes()), nil, nil
+	}
+	return nil, h.Sum(nil), nil
+}
+
+// streamedInput is a signing input consisting of a prefix and a streamed payload.
+// After streaming, digest is set, or input for EdDSA, which cannot sign a digest.
+type streamedInput struct {
+	a        algorithm
+	material any
+	prefix   []byte
+
+	input, digest []byte
+}
+
+// digestInputs reads the payload from r once and computes the digests of all inputs.
+// The payload is base64url encoded if encode is set. It is only buffered if one of
+// the inputs uses EdDSA.
+func digestInputs(inputs []*streamedInput, r io.Reader, encode bool) error {
+	var buf *bytes.Buffer
+	if slices.ContainsFunc(inputs, func(in *streamedInput) bool { return in.a.family == familyEdDSA }) {
+		buf = pool.GetBytesBuffer()
+		defer pool.PutBytesBuffer(buf)
+	}
+
+	hashes := make([]hash.Hash, len(inputs))
+	writers := make([]io.Writer, 0, len(inputs)+1)
+	for i, in := range inputs {
+		if hashes[i] = in.a.newHash(in.material); hashes[i] != nil {
+			if _, err := hashes[i].Write(in.prefix); err != nil {
+				return err
+			}
+			writers = append(writers, hashes[i])
+		}
+	}
+	if buf != nil {
+		writers = append(writers, buf)
+	}
+
+	if err := copyPayload(io.MultiWriter(writers...), r, encode); err != nil {
+		return err
+	}
+
+	for i, in := range inputs {
+		if hashes[i] != nil {
+			in.digest = hashes[i].Sum(nil)
+			continue
+		}
+		in.input = make([]byte, 0, len(in.prefix)+buf.Len())
+		in.input = append(append(in.input, in.prefix...), buf.Bytes()...)
+	}
+	return nil
+}
+
+// copyPayload copies the payload read from r to w, base64url encoded if encode is set.
+func copyPayload(w io.Writer, r io.Reader, encode bool) error {
+	if !encode {
+		_, err := io.Copy(w, r)
+		return err
+	}
+
+	enc := stdbase64.NewEncoder(stdbase64.RawURLEncoding, w)
+	if _, err := io.Copy(enc, r); err != nil {
+		return err
+	}
+	// The encoder writes the remaining partial block on close
+	return enc.Close()
+}
+
+// sign signs the input with the private or symmetric key.
+func (a algorithm) sign(k jwk.Key, input []byte) ([]byte, error) {
+	material, err := a.signingKey(k)
+	if err != nil {
+		return nil, err
+	}
+	return a.signDigest(material, input, a.digest(material, input))
+}
+
+// signingKey checks the key and returns the key material used for signing.
+func (a algorithm) signingKey(k jwk.Key) (any, error) {
+	if err := a.checkKey(k, jwk.KeyOpSign); err != nil {
+		return nil, err
+	}
+
+	switch a.family {
+	case familyHMAC:
+		return a.hmacSecret(k)
+	case familyRSAPKCS1, familyRSAPSS:
+		priv, ok := k.Raw().(*rsa.PrivateKey)
+		if !ok {
+			return nil, invalidKeyErr(a.alg, k, "expected RSA private key")
+		} else if priv.N.BitLen() < minRSAKeyBits {
+			return nil, invalidKeyErr(a.alg, k, fmt.Sprintf("key must have at least %d bits", minRSAKeyBits))
+		}
+		return priv, nil
+	case familyECDSA:
+		priv, ok := k.Raw().(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, invalidKeyErr(a.alg, k, "expected EC private key")
+		} else if err := a.checkCurve(k, &priv.PublicKey); err != nil {
+			return nil, err
+		}
+		return priv, nil
+	case familyEdDSA:
+		priv, ok := k.Raw().(ed25519.PrivateKey)
+		if !ok {
+			return nil, invalidKeyErr(a.alg, k, "expected Ed25519 private key")
+		}
+		return priv, nil
+	default:
+		return nil, unsupportedAlgorithmErr(a.alg)
+	}
+}
+
+// signDigest signs with key material returned by signingKey.
+// The digest is the hash of the input, or its MAC for HMAC algorithms. EdDSA uses the input.
+//
+//nolint:forcetypeassert
+func (a algorithm) signDigest(material any, input, digest []byte) ([]byte, error) {
+	switch a.family {
+	case familyHMAC:
+		return digest, nil
+	case familyRSAPKCS1:
+		return rsa.SignPKCS1v15(rand.Reader, material.(*rsa.PrivateKey), a.hash, digest)
+	case familyRSAPSS:
+		return rsa.SignPSS(rand.Reader, material.(*rsa.PrivateKey), a.hash, digest, &pssOptions)
+	case familyECDSA:
+		priv := material.(*ecdsa.PrivateKey)
+		r, s, err := ecdsa.Sign(rand.Reader, priv, digest)
+		if err != nil {
+			return nil, err
+		}
+
+		// The signature is the concatenation of r and s, each using the size of the curve
+		size := coordinateSize(&priv.PublicKey)
+		sig := make([]byte, 2*size)
+		r.FillBytes(sig[:size])
+		s.FillBytes(sig[size:])
+		return sig, nil
+	case familyEdDSA:
+		return ed25519.Sign(material.(ed25519.PrivateKey), input), nil
+	default:
+		return nil, unsupportedAlgorithmErr(a.alg)
+	}
+}
+
+// verify verifies the signature of the input with the public or symmetric key.
+// Private keys are converted to their public key.
+func (a algorithm) verify(k jwk.Key, input, sig []byte) error {
+	material, err := a.verificationKey(k)
+	if err != nil {
+		return err
+	}
+	return a.verifyDigest(material, input, a.digest(material, input), sig)
+}
+
+// verificationKey checks the key and returns the key material used for verification.
+func (a algorithm) verificationKey(k jwk.Key) (any, error) {
+	if pub, err := k.PublicKey(); err == nil {
+		k = pub
+	} else if !errors.Is(err, jwk.ErrNoPublicKey) {
+		return nil, err
+	}
+
+	if err := a.checkKey(k, jwk.KeyOpVerify); err != nil {
+		return nil, err
+	}
+
+	switch a.family {
+	case familyHMAC:
+		return a.hmacSecret(k)
+	case familyRSAPKCS1, familyRSAPSS:
+		pub, ok := k.Raw().(*rsa.PublicKey)
+		if !ok {
+			return nil, invalidKeyErr(a.alg, k, "expected RSA public key")
+		} else if pub.N.BitLen() < minRSAKeyBits {
+			return nil, invalidKeyErr(a.alg, k, fmt.Sprintf("key must have at least %d bits", minRSAKeyBits))
+		}
+		return pub, nil
+	case familyECDSA:
+		pub, ok := k.Raw().(*ecdsa.PublicKey)
+		if !ok {
+			return nil, invalidKeyErr(a.alg, k, "expected EC public key")
+		} else if err := a.checkCurve(k, pub); err != nil {
+			return nil, err
+		}
+		return pub, nil
+	case familyEdDSA:
+		pub, ok := k.Raw().(ed25519.PublicKey)
+		if !ok {
+			return nil, invalidKeyErr(a.alg, k, "expected Ed25519 public key")
+		}
+		return pub, nil
+	default:
+		return nil, unsupportedAlgorithmErr(a.alg)
+	}
+}
+
+// verifyDigest verifies the signature with key material returned by verificationKey.
+// The digest is the hash of the input, or its MAC for HMAC algorithms. EdDSA uses the input.
+//
+//nolint:forcetypeassert
+func (a algorithm) verifyDigest(material any, input, digest, sig []byte) error {
+	var valid bool
+	switch a.family {
+	case familyHMAC:
+		valid = hmac.Equal(sig, digest)
+	case familyRSAPKCS1:
+		valid = rsa.VerifyPKCS1v15(material.(*rsa.PublicKey), a.hash, digest, sig) == nil
+	case familyRSAPSS:
+		valid = rsa.VerifyPSS(material.(*rsa.PublicKey), a.hash, digest, sig, &pssOptions) == nil
+	case familyECDSA:
+		pub := material.(*ecdsa.PublicKey)
+		size := coordinateSize(pub)
+		if len(sig) != 2*size {
+			return ErrInvalidSignature
+		}
+
+		r, s := pool.GetBigInt(), pool.GetBigInt()
+		defer pool.PutBigInt(r)
+		defer pool.PutBigInt(s)
+		valid = ecdsa.Verify(pub, digest, r.SetBytes(sig[:size]), s.SetBytes(sig[size:]))
+	case familyEdDSA:
+		valid = ed25519.Verify(material.(ed25519.PublicKey), input, sig)
+	default:
+		return unsupportedAlgorithmErr(a.alg)
+	}
+
+	if !valid {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (a algorithm) hmacSecret(k jwk.Key) ([]byte, error) {
+	secret, ok := k.Raw().([]byte)
+	if !ok {
+		return nil, invalidKeyErr(a.alg, k, "expected symmetric key")
+	} else if len(secret) < a.hash.Size() {
+		return nil, invalidKeyErr(a.alg, k, fmt.Sprintf("key must have at least %d bytes", a.hash.Size()))
+	}
+	return secret, nil
+}
+
+func (a algorithm) checkCurve(k jwk.Key, pub *ecdsa.PublicKey) error {
+	if crv := jwk.Curve(pub.Curve.Params().Name); crv != a.crv {
+		return invalidKeyErr(a.alg, k, fmt.Sprintf("expected curve %s, got %s", a.crv, crv))
+	}
+	return nil
+}
+
+func coordinateSize(pub *ecdsa.PublicKey) int {
+	return (pub.Curve.Params().BitSize + 7) / 8
+}
@@ -0,0 +1,164 @@
+package jws_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jgraeger/jwgo"
+	"github.com/jgraeger/jwgo/jwa"
+	"github.com/jgraeger/jwgo/jwk"
+	"github.com/jgraeger/jwgo/jws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnencodedPayloadTestVectors(t *testing.T) {
+	t.Parallel()
+
+	// RFC 7797, Section 4
+	key, err := jwk.ParseString(`{"kty":"oct","k":"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow"}`)
+	require.NoError(t, err)
+	keys := jws.StaticKey(key)
+
+	payload, err := jws.Verify([]byte("eyJhbGciOiJIUzI1NiJ9.JC4wMg.5mvfOroL-g7HyqJoozehmsaqmvTYGEq5jTI1gVvoEoQ"), keys)
+	require.NoError(t, err)
+	assert.Equal(t, "$.02", string(payload))
+
+	detached := []byte("eyJhbGciOiJIUzI1NiIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19..A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY")
+	require.NoError(t, jws.VerifyDetached(detached, strings.NewReader("$.02"), keys))
+	assert.ErrorIs(t, jws.VerifyDetached(detached, strings.NewReader("$.03"), keys), jws.ErrInvalidSignature)
+
+	// The payload is part of the signed content
+	_, err = jws.Verify(detached, keys)
+	assert.ErrorIs(t, err, jws.ErrInvalidSignature)
+}
+
+func TestSignVerifyDetached(t *testing.T) {
+	t.Parallel()
+
+	// Large enough to be copied in several chunks
+	payload := make([]byte, 3<<20)
+	_, err := rand.Read(payload)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		alg jwa.SignatureAlgorithm
+		key jwk.Key
+	}{
+		{alg: jwa.HS256, key: mustGenerate(t, jwk.Oct)},
+		{alg: jwa.PS256, key: mustGenerate(t, jwk.RSA, jwk.WithAlgorithm(jwa.KeyAlgorithmMustFrom(jwa.PS256)))},
+		{alg: jwa.ES384, key: mustGenerate(t, jwk.EC, jwk.WithCurve(jwk.P384))},
+		{alg: jwa.EdDSA, key: mustGenerate(t, jwk.OKP)},
+	} {
+		tc := tt
+		t.Run(tc.alg.String(), func(t *testing.T) {
+			t.Parallel()
+
+			keys := jws.StaticKey(tc.key)
+			for _, opts := range [][]jws.SignOption{nil, {jws.WithUnencodedPayload()}} {
+				token, err := jws.SignDetached(bytes.NewReader(payload), tc.alg, tc.key, opts...)
+				require.NoError(t, err)
+				assert.Contains(t, string(token), "..")
+
+				require.NoError(t, jws.VerifyDetached(token, bytes.NewReader(payload), keys))
+				err = jws.VerifyDetached(token, io.LimitReader(bytes.NewReader(payload), int64(len(payload)-1)), keys)
+				assert.ErrorIs(t, err, jws.ErrInvalidSignature)
+			}
+
+			// Required key IDs are counted by the verifying key, not the claimed key ID
+			forged, err := jws.SignDetached(bytes.NewReader(payload), tc.alg, tc.key, jws.WithKeyID("other"))
+			require.NoError(t, err)
+			err = jws.VerifyDetached(forged, bytes.NewReader(payload), keys, jws.WithRequiredKeyIDs("other"))
+			assert.ErrorIs(t, err, jwk.ErrKeyNotFound)
+
+			// A detached signature equals the signature of the attached payload
+			attached, err := jws.Sign(payload, tc.alg, tc.key)
+			require.NoError(t, err)
+			parts := strings.Split(string(attached), ".")
+			detached := []byte(parts[0] + ".." + parts[2])
+			require.NoError(t, jws.VerifyDetached(detached, bytes.NewReader(payload), keys))
+
+			err = jws.VerifyDetached(attached, bytes.NewReader(payload), keys)
+			assert.ErrorIs(t, err, jwgo.ErrTokenMalformed)
+		})
+	}
+}
+
+func TestUnencodedPayload(t *testing.T) {
+	t.Parallel()
+
+	key := mustGenerate(t, jwk.Oct)
+	payload := []byte(`{"event":"push","ref":"refs/heads/main"}`)
+
+	token, err := jws.Sign(payload, jwa.HS256, key, jws.WithUnencodedPayload())
+	require.NoError(t, err)
+	assert.Contains(t, string(token), string(payload))
+
+	verified, err := jws.Verify(token, jws.StaticKey(key))
+	require.NoError(t, err)
+	assert.Equal(t, payload, verified)
+
+	v, err := jws.NewVerifier(jwa.HS256, key)
+	require.NoError(t, err)
+	verified, err = v.Verify(nil, token)
+	require.NoError(t, err)
+	assert.Equal(t, payload, verified)
+
+	_, err = jws.Sign([]byte("$.02"), jwa.HS256, key, jws.WithUnencodedPayload())
+	assert.Error(t, err)
+
+	// JSON serialization
+	b64 := false
+	m, err := jws.SignJSON(payload, jws.Signer{Algorithm: jwa.HS256, Key: key, Protected: jws.Header{Base64: &b64}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{jws.HeaderB64}, m.Signatures()[0].Protected.Critical)
+
+	flattened, err := m.MarshalFlattenedJSON()
+	require.NoError(t, err)
+	verified, err = jws.VerifyJSON(flattened, jws.StaticKey(key))
+	require.NoError(t, err)
+	assert.Equal(t, payload, verified)
+
+	_, err = jws.SignJSON([]byte{0xff}, jws.Signer{Algorithm: jwa.HS256, Key: key, Protected: jws.Header{Base64: &b64}})
+	assert.Error(t, err)
+	_, err = jws.SignJSON(payload,
+		jws.Signer{Algorithm: jwa.HS256, Key: key, Protected: jws.Header{Base64: &b64}},
+		jws.Signer{Algorithm: jwa.HS256, Key: key},
+	)
+	assert.Error(t, err)
+}
+
+func TestVerifyCritical(t *testing.T) {
+	t.Parallel()
+
+	key := mustGenerate(t, jwk.Oct)
+	v, err := jws.NewVerifier(jwa.HS256, key)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name     string
+		header   string
+		expected error
+	}{
+		{name: "unknown critical header", header: `{"alg":"HS256","exp":1,"crit":["exp"]}`, expected: jws.ErrUnsupportedCritical},
+		{name: "empty crit", header: `{"alg":"HS256","crit":[]}`, expected: jwgo.ErrTokenMalformed},
+		{name: "missing critical header", header: `{"alg":"HS256","crit":["b64"]}`, expected: jwgo.ErrTokenMalformed},
+		{name: "b64 not critical", header: `{"alg":"HS256","b64":false}`, expected: jwgo.ErrTokenMalformed},
+	} {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			token := []byte(base64.RawURLEncoding.EncodeToString([]byte(tc.header)) + ".cGF5bG9hZA.c2ln")
+
+			_, err := jws.Verify(token, jws.StaticKey(key))
+			assert.ErrorIs(t, err, tc.expected)
+			_, err = v.Verify(nil, token)
+			assert.ErrorIs(t, err, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package jws
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jgraeger/jwgo"
+	"github.com/jgraeger/jwgo/jwa"
+	"github.com/jgraeger/jwgo/jwk"
+)
+
+var (
+	ErrInvalidSignature     = errors.New("signature is invalid")
+	ErrUnsupportedAlgorithm = errors.New("unsupported algorithm")
+	ErrInvalidKey           = errors.New("key cannot be used with algorithm")
+	ErrMissingSignature     = errors.New("required signature is missing")
+	ErrUnsupportedCritical  = errors.New("unsupported critical header")
+)
+
+// internal errors
+var (
+	errInvalidSegmentCount = fmt.Errorf("%w: token contains an invalid number of segments", jwgo.ErrTokenMalformed)
+)
+
+func unsupportedAlgorithmErr(alg jwa.SignatureAlgorithm) error {
+	return fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, alg)
+}
+
+func invalidKeyErr(alg jwa.SignatureAlgorithm, k jwk.Key, reason string) error {
+	return fmt.Errorf("%w: %s key for %s: %s", ErrInvalidKey, k.Type(), alg, reason)
+}
+
+func malformedErr(part string, err error) error {
+	return fmt.Errorf("%w: %s: %w", jwgo.ErrTokenMalformed, part, err)
+}
+
+func unsupportedCriticalErr(name string) error {
+	return fmt.Errorf("%w: %s", ErrUnsupportedCritical, name)
+}
+
+func missingSignatureErr(kid string) error {
+	return fmt.Errorf("%w: %s", ErrMissingSignature, kid)
+}
+
+func keyNotFoundErr(kid string) error {
+	return fmt.Errorf("%w: %s", jwk.ErrKeyNotFound, kid)
+}
+
+func duplicateHeaderErr(name string) error {
+	return malformedErr("header", fmt.Errorf("%s is both protected and unprotected", name))
+}
@@ -0,0 +1,470 @@
+package jws
+
+import (
+	"bytes"
+	"crypto/sha1" //nolint:gosec // x5t is defined as SHA-1 thumbprint
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/goccy/go-json"
+	"github.com/jgraeger/jwgo/internal/base64"
+	"github.com/jgraeger/jwgo/internal/jsonenc"
+	"github.com/jgraeger/jwgo/internal/pool"
+	"github.com/jgraeger/jwgo/jwa"
+	"github.com/jgraeger/jwgo/jwk"
+)
+
+// Registered header parameter names (RFC 7515, Section 4.1 and RFC 7797, Section 3).
+const (
+	HeaderAlg     = "alg"
+	HeaderKid     = "kid"
+	HeaderTyp     = "typ"
+	HeaderCty     = "cty"
+	HeaderJku     = "jku"
+	HeaderJwk     = "jwk"
+	HeaderX5u     = "x5u"
+	HeaderX5c     = "x5c"
+	HeaderX5t     = "x5t"
+	HeaderX5tS256 = "x5t#S256"
+	HeaderCrit    = "crit"
+	HeaderB64     = "b64"
+)
+
+// headerMapSize is the initial size of maps for header parameters.
+const headerMapSize = 4
+
+// Header is the JOSE header of a JWS.
+type Header struct {
+	Algorithm   jwa.SignatureAlgorithm
+	KeyID       string
+	Type        string
+	ContentType string
+	// URL of a JWK set that contains the key (`jku` header)
+	JWKSetURL string
+	// Public key of the signature (`jwk` header)
+	JWK jwk.Key
+	// URL of the X.509 certificate chain (`x5u` header)
+	X509URL string
+	// X.509 certificate chain, with the leaf certificate first (`x5c` header)
+	X509CertChain []*x509.Certificate
+	// SHA-1 thumbprint of the leaf certificate (`x5t` header)
+	X509Thumbprint []byte
+	// SHA-256 thumbprint of the leaf certificate (`x5t#S256` header)
+	X509ThumbprintS256 []byte
+	// Base64 is false if the payload is not base64url encoded. It must be listed in Critical.
+	Base64 *bool
+	// Critical lists the extensions that must be understood (`crit` header)
+	Critical []string
+	// Extensions holds the header parameters that are not registered
+	Extensions map[string]json.RawMessage
+}
+
+// CriticalHandler processes the value of a critical header extension.
+// A verification fails if the handler returns an error.
+type CriticalHandler func(value json.RawMessage) error
+
+// unencoded reports whether the payload is not base64url encoded.
+func (h *Header) unencoded() bool {
+	return h.Base64 != nil && !*h.Base64
+}
+
+// has reports whether the header parameter is present.
+func (h *Header) has(name string) bool {
+	switch name {
+	case HeaderAlg:
+		return h.Algorithm != ""
+	case HeaderKid:
+		return h.KeyID != ""
+	case HeaderTyp:
+		return h.Type != ""
+	case HeaderCty:
+		return h.ContentType != ""
+	case HeaderJku:
+		return h.JWKSetURL != ""
+	case HeaderJwk:
+		return h.JWK != nil
+	case HeaderX5u:
+		return h.X509URL != ""
+	case HeaderX5c:
+		return len(h.X509CertChain) > 0
+	case HeaderX5t:
+		return len(h.X509Thumbprint) > 0
+	case HeaderX5tS256:
+		return len(h.X509ThumbprintS256) > 0
+	case HeaderCrit:
+		return h.Critical != nil
+	case HeaderB64:
+		return h.Base64 != nil
+	default:
+		_, ok := h.Extensions[name]
+		return ok
+	}
+}
+
+// registered reports whether the name is a header parameter of RFC 7515, which
+// must not be listed as critical.
+func registered(name string) bool {
+	switch name {
+	case HeaderAlg, HeaderKid, HeaderTyp, HeaderCty, HeaderJku, HeaderJwk,
+		HeaderX5u, HeaderX5c, HeaderX5t, HeaderX5tS256, HeaderCrit:
+		return true
+	default:
+		return false
+	}
+}
+
+// validateCritical checks that the critical headers are extensions that are present
+// (RFC 7515, Section 4.1.11) and that `b64` is critical (RFC 7797, Section 6).
+func (h *Header) validateCritical() error {
+	if h.Critical != nil && len(h.Critical) == 0 {
+		return malformedErr("header", errors.New("crit must not be empty"))
+	}
+	for i, name := range h.Critical {
+		switch {
+		case registered(name):
+			return malformedErr("header", fmt.Errorf("registered header %s must not be critical", name))
+		case slices.Contains(h.Critical[:i], name):
+			return malformedErr("header", fmt.Errorf("duplicate critical header %s", name))
+		case !h.has(name):
+			return malformedErr("header", fmt.Errorf("critical header %s is missing", name))
+		}
+	}
+
+	if h.Base64 != nil && !slices.Contains(h.Critical, HeaderB64) {
+		return malformedErr("header", fmt.Errorf("%s must be critical", HeaderB64))
+	}
+	return nil
+}
+
+// checkCritical checks that all critical headers are understood. Extensions are
+// understood if a handler is registered for them, which must accept the value.
+func (h *Header) checkCritical(handlers map[string]CriticalHandler) error {
+	if err := h.validateCritical(); err != nil {
+		return err
+	}
+
+	for _, name := range h.Critical {
+		if name == HeaderB64 {
+			continue
+		}
+
+		handler, ok := handlers[name]
+		if !ok {
+			return unsupportedCriticalErr(name)
+		}
+		if err := handler(h.Extensions[name]); err != nil {
+			return fmt.Errorf("critical header %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// hasCriticalExtensions reports whether critical headers other than `b64` are present.
+func (h *Header) hasCriticalExtensions() bool {
+	return slices.ContainsFunc(h.Critical, func(name string) bool { return name != HeaderB64 })
+}
+
+// merge returns the union of the header and the unprotected header.
+// Header parameters must not occur in both headers.
+func (h Header) merge(u *Header) (Header, error) {
+	if u == nil {
+		return h, nil
+	}
+
+	// Both headers must be integrity protected
+	if u.Base64 != nil || u.Critical != nil {
+		return h, malformedErr("header", errors.New("crit and b64 must be protected"))
+	}
+
+	for _, name := range []string{
+		HeaderAlg, HeaderKid, HeaderTyp, HeaderCty, HeaderJku, HeaderJwk,
+		HeaderX5u, HeaderX5c, HeaderX5t, HeaderX5tS256,
+	} {
+		if u.has(name) && h.has(name) {
+			return h, duplicateHeaderErr(name)
+		}
+	}
+	for name := range u.Extensions {
+		if h.has(name) {
+			return h, duplicateHeaderErr(name)
+		}
+	}
+
+	if u.Algorithm != "" {
+		h.Algorithm = u.Algorithm
+	}
+	for _, m := range []struct {
+		dst *string
+		src string
+	}{
+		{dst: &h.KeyID, src: u.KeyID},
+		{dst: &h.Type, src: u.Type},
+		{dst: &h.ContentType, src: u.ContentType},
+		{dst: &h.JWKSetURL, src: u.JWKSetURL},
+		{dst: &h.X509URL, src: u.X509URL},
+	} {
+		if m.src != "" {
+			*m.dst = m.src
+		}
+	}
+	if u.JWK != nil {
+		h.JWK = u.JWK
+	}
+	if len(u.X509CertChain) > 0 {
+		h.X509CertChain = u.X509CertChain
+	}
+	if len(u.X509Thumbprint) > 0 {
+		h.X509Thumbprint = u.X509Thumbprint
+	}
+	if len(u.X509ThumbprintS256) > 0 {
+		h.X509ThumbprintS256 = u.X509ThumbprintS256
+	}
+	if len(u.Extensions) > 0 {
+		// Copy the extensions, as the map is shared with the protected header
+		h.Extensions = maps.Clone(h.Extensions)
+		if h.Extensions == nil {
+			h.Extensions = make(map[string]json.RawMessage, len(u.Extensions))
+		}
+		maps.Copy(h.Extensions, u.Extensions)
+	}
+	return h, nil
+}
+
+// MarshalJSON encodes the header as JSON object. Extensions are sorted by name.
+func (h Header) MarshalJSON() ([]byte, error) {
+	buf := pool.GetBytesBuffer()
+	defer pool.PutBytesBuffer(buf)
+
+	if err := h.writeJSON(buf); err != nil {
+		return nil, err
+	}
+	return bytes.Clone(buf.Bytes()), nil
+}
+
+func (h *Header) writeJSON(buf *bytes.Buffer) error {
+	buf.WriteByte('{')
+	for _, m := range []struct {
+		name  string
+		value string
+	}{
+		{name: HeaderAlg, value: h.Algorithm.String()},
+		{name: HeaderKid, value: h.KeyID},
+		{name: HeaderTyp, value: h.Type},
+		{name: HeaderCty, value: h.ContentType},
+		{name: HeaderJku, value: h.JWKSetURL},
+		{name: HeaderX5u, value: h.X509URL},
+	} {
+		if m.value != "" {
+			writeMemberName(buf, m.name)
+			jsonenc.WriteString(buf, m.value)
+		}
+	}
+
+	if h.JWK != nil {
+		b, err := h.JWK.MarshalJSON()
+		if err != nil {
+			return fmt.Errorf("%s: %w", HeaderJwk, err)
+		}
+		writeMemberName(buf, HeaderJwk)
+		buf.Write(b)
+	}
+	if len(h.X509CertChain) > 0 {
+		writeMemberName(buf, HeaderX5c)
+		buf.WriteByte('[')
+		for i, cert := range h.X509CertChain {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('"')
+			buf.WriteString(base64.StdEncoding.EncodeToString(cert.Raw))
+			buf.WriteByte('"')
+		}
+		buf.WriteByte(']')
+	}
+	if len(h.X509Thumbprint) > 0 {
+		writeMemberName(buf, HeaderX5t)
+		buf.WriteByte('"')
+		writeBase64(buf, h.X509Thumbprint)
+		buf.WriteByte('"')
+	}
+	if len(h.X509ThumbprintS256) > 0 {
+		writeMemberName(buf, HeaderX5tS256)
+		buf.WriteByte('"')
+		writeBase64(buf, h.X509ThumbprintS256)
+		buf.WriteByte('"')
+	}
+	if h.Base64 != nil {
+		writeMemberName(buf, HeaderB64)
+		if *h.Base64 {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+	}
+	if h.Critical != nil {
+		writeMemberName(buf, HeaderCrit)
+		buf.WriteByte('[')
+		for i, name := range h.Critical {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			jsonenc.WriteString(buf, name)
+		}
+		buf.WriteByte(']')
+	}
+
+	names := make([]string, 0, len(h.Extensions))
+	for name := range h.Extensions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if registered(name) || name == HeaderB64 {
+			return fmt.Errorf("extension %s is a registered header", name)
+		}
+		value := h.Extensions[name]
+		if !json.Valid(value) {
+			return fmt.Errorf("extension %s is not valid JSON", name)
+		}
+		writeMemberName(buf, name)
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+	return nil
+}
+
+// writeMemberName writes the quoted name and colon of an object member into buf,
+// preceded by a comma unless it is the first member.
+func writeMemberName(buf *bytes.Buffer, name string) {
+	if b := buf.Bytes(); len(b) > 0 && b[len(b)-1] != '{' {
+		buf.WriteByte(',')
+	}
+	jsonenc.WriteString(buf, name)
+	buf.WriteByte(':')
+}
+
+// UnmarshalJSON decodes the header from a JSON object.
+// Duplicate header parameters are rejected (RFC 7515, Section 4).
+func (h *Header) UnmarshalJSON(data []byte) error {
+	*h = Header{}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil {
+		return err
+	} else if t != json.Delim('{') {
+		return errors.New("header must be a JSON object")
+	}
+
+	seen := make(map[string]struct{}, headerMapSize)
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name, ok := t.(string)
+		if !ok {
+			return fmt.Errorf("expected member name, got %T", t)
+		}
+
+		if _, ok := seen[name]; ok {
+			return fmt.Errorf("duplicate header %s", name)
+		}
+		seen[name] = struct{}{}
+
+		if err := h.decodeMember(dec, name); err != nil {
+			return fmt.Errorf("header %s: %w", name, err)
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (h *Header) decodeMember(dec *json.Decoder, name string) error {
+	switch name {
+	case HeaderAlg:
+		return dec.Decode(&h.Algorithm)
+	case HeaderKid:
+		return dec.Decode(&h.KeyID)
+	case HeaderTyp:
+		return dec.Decode(&h.Type)
+	case HeaderCty:
+		return dec.Decode(&h.ContentType)
+	case HeaderJku:
+		return dec.Decode(&h.JWKSetURL)
+	case HeaderX5u:
+		return dec.Decode(&h.X509URL)
+	case HeaderJwk:
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		k, err := jwk.Parse(raw)
+		if err != nil {
+			return err
+		}
+		// The header contains the public key, private members are ignored
+		h.JWK, err = k.PublicKey()
+		return err
+	case HeaderX5c:
+		var chain []string
+		if err := dec.Decode(&chain); err != nil {
+			return err
+		} else if len(chain) == 0 {
+			return errors.New("certificate chain must not be empty")
+		}
+
+		h.X509CertChain = make([]*x509.Certificate, len(chain))
+		for i, c := range chain {
+			der, err := base64.StdEncoding.DecodeString(c)
+			if err != nil {
+				return err
+			}
+			if h.X509CertChain[i], err = x509.ParseCertificate(der); err != nil {
+				return err
+			}
+		}
+		return nil
+	case HeaderX5t:
+		return decodeThumbprint(dec, &h.X509Thumbprint, sha1.Size)
+	case HeaderX5tS256:
+		return decodeThumbprint(dec, &h.X509ThumbprintS256, sha256.Size)
+	case HeaderB64:
+		return dec.Decode(&h.Base64)
+	case HeaderCrit:
+		return dec.Decode(&h.Critical)
+	default:
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		if h.Extensions == nil {
+			h.Extensions = make(map[string]json.RawMessage, headerMapSize)
+		}
+		h.Extensions[name] = raw
+		return nil
+	}
+}
+
+func decodeThumbprint(dec *json.Decoder, dst *[]byte, size int) error {
+	var s string
+	if err := dec.Decode(&s); err != nil {
+		return err
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	} else if len(b) != size {
+		return fmt.Errorf("thumbprint must be %d bytes", size)
+	}
+	*dst = b
+	return nil
+}
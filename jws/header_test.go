@@ -0,0 +1,180 @@
+package jws_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // x5t is defined as SHA-1 thumbprint
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/jgraeger/jwgo"
+	"github.com/jgraeger/jwgo/jwa"
+	"github.com/jgraeger/jwgo/jwk"
+	"github.com/jgraeger/jwgo/jws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderJSON(t *testing.T) {
+	t.Parallel()
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "signer"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}, &x509.Certificate{}, &priv.PublicKey, priv)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	key, err := jwk.FromCrypto(&priv.PublicKey, jwk.WithKeyID("signer"))
+	require.NoError(t, err)
+
+	b64 := false
+	x5t := sha1.Sum(der) //nolint:gosec
+	x5tS256 := sha256.Sum256(der)
+	h := jws.Header{
+		Algorithm:          jwa.ES256,
+		KeyID:              "signer",
+		Type:               "JOSE",
+		ContentType:        "application/json",
+		JWKSetURL:          "https://example.com/jwks.json",
+		JWK:                key,
+		X509URL:            "https://example.com/chain.pem",
+		X509CertChain:      []*x509.Certificate{cert},
+		X509Thumbprint:     x5t[:],
+		X509ThumbprintS256: x5tS256[:],
+		Base64:             &b64,
+		Critical:           []string{"b64", "example.com/policy"},
+		Extensions: map[string]json.RawMessage{
+			"example.com/policy": json.RawMessage(`{"level":2}`),
+			"iat":                json.RawMessage(`1300819380`),
+		},
+	}
+
+	b, err := h.MarshalJSON()
+	require.NoError(t, err)
+
+	var members map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(b, &members))
+	assert.JSONEq(t, `"ES256"`, string(members["alg"]))
+	assert.JSONEq(t, `["`+base64.StdEncoding.EncodeToString(der)+`"]`, string(members["x5c"]))
+	assert.JSONEq(t, `"`+base64.RawURLEncoding.EncodeToString(x5t[:])+`"`, string(members["x5t"]))
+	assert.JSONEq(t, `false`, string(members["b64"]))
+	assert.JSONEq(t, `{"level":2}`, string(members["example.com/policy"]))
+	assert.Len(t, members, 14)
+
+	var decoded jws.Header
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, key.Raw(), decoded.JWK.Raw())
+	decoded.JWK = key
+	assert.Equal(t, h, decoded)
+
+	// Private keys are reduced to the public key
+	privKey, err := jwk.FromCrypto(priv)
+	require.NoError(t, err)
+	b, err = jws.Header{Algorithm: jwa.ES256, JWK: privKey}.MarshalJSON()
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, &priv.PublicKey, decoded.JWK.Raw())
+}
+
+func TestHeaderJSONErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name string
+		json string
+	}{
+		{name: "not an object", json: `["ES256"]`},
+		{name: "duplicate member", json: `{"alg":"ES256","alg":"none"}`},
+		{name: "kid is not a string", json: `{"kid":1}`},
+		{name: "malformed jwk", json: `{"jwk":{"kty":"EC"}}`},
+		{name: "symmetric jwk", json: `{"jwk":{"kty":"oct","k":"c2VjcmV0"}}`},
+		{name: "empty x5c", json: `{"x5c":[]}`},
+		{name: "malformed x5c", json: `{"x5c":["bm90IGEgY2VydGlmaWNhdGU="]}`},
+		{name: "x5t of wrong size", json: `{"x5t":"c2hvcnQ"}`},
+		{name: "b64 is not a boolean", json: `{"b64":"false"}`},
+		{name: "crit is not an array", json: `{"crit":"exp"}`},
+	} {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var h jws.Header
+			assert.Error(t, json.Unmarshal([]byte(tc.json), &h))
+		})
+	}
+
+	_, err := jws.Header{Extensions: map[string]json.RawMessage{"kid": json.RawMessage(`"kid"`)}}.MarshalJSON()
+	assert.Error(t, err)
+	_, err = jws.Header{Extensions: map[string]json.RawMessage{"ext": json.RawMessage(`{`)}}.MarshalJSON()
+	assert.Error(t, err)
+}
+
+func TestCriticalHandler(t *testing.T) {
+	t.Parallel()
+
+	key := mustGenerate(t, jwk.Oct)
+	header := jws.Header{
+		Critical:   []string{"example.com/level"},
+		Extensions: map[string]json.RawMessage{"example.com/level": json.RawMessage(`2`)},
+	}
+	token, err := jws.Sign([]byte("payload"), jwa.HS256, key, jws.WithHeader(header))
+	require.NoError(t, err)
+
+	errTooLow := errors.New("level too low")
+	var calls int
+	minLevel := func(level int) jws.VerifyOption {
+		return jws.WithCriticalHandler("example.com/level", func(value json.RawMessage) error {
+			calls++
+			var v int
+			if err := json.Unmarshal(value, &v); err != nil {
+				return err
+			} else if v < level {
+				return errTooLow
+			}
+			return nil
+		})
+	}
+
+	_, err = jws.Verify(token, jws.StaticKey(key))
+	assert.ErrorIs(t, err, jws.ErrUnsupportedCritical)
+	_, err = jws.Verify(token, jws.StaticKey(key), minLevel(3))
+	assert.ErrorIs(t, err, errTooLow)
+	payload, err := jws.Verify(token, jws.StaticKey(key), minLevel(2))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("payload"), payload)
+
+	// The fast path calls the handler for every token
+	v, err := jws.NewVerifier(jwa.HS256, key)
+	require.NoError(t, err)
+	_, err = v.Verify(nil, token)
+	assert.ErrorIs(t, err, jws.ErrUnsupportedCritical)
+
+	v, err = jws.NewVerifier(jwa.HS256, key, minLevel(2))
+	require.NoError(t, err)
+	calls = 0
+	for i := 0; i < 2; i++ {
+		_, err = v.Verify(nil, token)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, calls)
+
+	// Registered headers must not be critical
+	_, err = jws.Sign([]byte("payload"), jwa.HS256, key, jws.WithHeader(jws.Header{Type: "JWT", Critical: []string{"typ"}}))
+	assert.ErrorIs(t, err, jwgo.ErrTokenMalformed)
+	// Critical headers must be present
+	_, err = jws.Sign([]byte("payload"), jwa.HS256, key, jws.WithHeader(jws.Header{Critical: []string{"exp"}}))
+	assert.ErrorIs(t, err, jwgo.ErrTokenMalformed)
+}
@@ -0,0 +1,453 @@
+package jws
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/goccy/go-json"
+	"github.com/jgraeger/jwgo/internal/base64"
+	"github.com/jgraeger/jwgo/internal/pool"
+	"github.com/jgraeger/jwgo/jwa"
+	"github.com/jgraeger/jwgo/jwk"
+)
+
+// Message is a JWS in general or flattened JSON serialization (RFC 7515, Section 7.2).
+type Message struct {
+	payload        []byte
+	encodedPayload []byte
+	signatures     []*Signature
+	// detached messages are serialized without payload (RFC 7515, Appendix F)
+	detached bool
+}
+
+// Signature is one of the signatures of a Message.
+type Signature struct {
+	// Protected is the integrity protected header.
+	Protected Header
+	// Unprotected is the per-signature header that is not integrity protected, or nil.
+	Unprotected *Header
+
+	// encodedProtected is the protected header as it was signed
+	encodedProtected []byte
+	value            []byte
+}
+
+// Signer signs a Message with a key.
+type Signer struct {
+	Algorithm jwa.SignatureAlgorithm
+	Key       jwk.Key
+	// Protected sets the members of the protected header other than `alg`.
+	// The `kid` member defaults to the ID of the key, unless it is set in either header.
+	Protected Header
+	// Unprotected is an optional per-signature unprotected header.
+	Unprotected *Header
+}
+
+// jsonSignature is the representation of a signature in JSON serialization.
+type jsonSignature struct {
+	Protected string  `json:"protected,omitempty"`
+	Header    *Header `json:"header,omitempty"`
+	Signature string  `json:"signature"`
+}
+
+// jsonMessage is the representation of a message in JSON serialization.
+// It contains either the signatures or the members of a single signature.
+type jsonMessage struct {
+	Payload    *string         `json:"payload,omitempty"`
+	Signatures []jsonSignature `json:"signatures,omitempty"`
+	Protected  string          `json:"protected,omitempty"`
+	Header     *Header         `json:"header,omitempty"`
+	Signature  *string         `json:"signature,omitempty"`
+}
+
+// SignJSON signs the payload with each of the signers.
+func SignJSON(payload []byte, signers ...Signer) (*Message, error) {
+	if len(signers) == 0 {
+		return nil, errors.New("no signers")
+	}
+
+	m := &Message{
+		payload:        bytes.Clone(payload),
+		encodedPayload: []byte(base64.RawURLEncoding.EncodeToString(payload)),
+		signatures:     make([]*Signature, 0, len(signers)),
+	}
+
+	// The payload is a JSON string, so an unencoded payload must be valid UTF-8
+	unencoded := signers[0].Protected.unencoded()
+	if unencoded {
+		if !utf8.Valid(payload) {
+			return nil, errors.New("unencoded payload must be valid UTF-8")
+		}
+		m.encodedPayload = m.payload
+	}
+
+	for i, s := range signers {
+		if s.Protected.unencoded() != unencoded {
+			return nil, fmt.Errorf("signer %d: %s must be the same for all signatures", i, HeaderB64)
+		}
+
+		a, sig, err := newSignature(s)
+		if err != nil {
+			return nil, fmt.Errorf("signer %d: %w", i, err)
+		}
+		sig.value, err = a.sign(s.Key, m.signingInput(sig))
+		if err != nil {
+			return nil, fmt.Errorf("signer %d: %w", i, err)
+		}
+		m.signatures = append(m.signatures, sig)
+	}
+	return m, nil
+}
+
+// SignJSONDetached signs the payload read from r with each of the signers and returns
+// a message that is serialized without payload (RFC 7515, Appendix F). The payload is
+// read once and streamed into the hash of every signer. It is only buffered if one of
+// the signers uses EdDSA, which signs the whole input.
+func SignJSONDetached(r io.Reader, signers ...Signer) (*Message, error) {
+	if len(signers) == 0 {
+		return nil, errors.New("no signers")
+	}
+
+	m := &Message{
+		signatures: make([]*Signature, 0, len(signers)),
+		detached:   true,
+	}
+
+	unencoded := signers[0].Protected.unencoded()
+	inputs := make([]*streamedInput, 0, len(signers))
+	for i, s := range signers {
+		if s.Protected.unencoded() != unencoded {
+			return nil, fmt.Errorf("signer %d: %s must be the same for all signatures", i, HeaderB64)
+		}
+
+		a, sig, err := newSignature(s)
+		if err != nil {
+			return nil, fmt.Errorf("signer %d: %w", i, err)
+		}
+		material, err := a.signingKey(s.Key)
+		if err != nil {
+			return nil, fmt.Errorf("signer %d: %w", i, err)
+		}
+		m.signatures = append(m.signatures, sig)
+		inputs = append(inputs, &streamedInput{a: a, material: material, prefix: sig.signingPrefix()})
+	}
+
+	if err := digestInputs(inputs, r, !unencoded); err != nil {
+		return nil, err
+	}
+	for i, in := range inputs {
+		value, err := in.a.signDigest(in.material, in.input, in.digest)
+		if err != nil {
+			return nil, fmt.Errorf("signer %d: %w", i, err)
+		}
+		m.signatures[i].value = value
+	}
+	return m, nil
+}
+
+// newSignature returns the algorithm of the signer and a signature with its protected header.
+func newSignature(s Signer) (algorithm, *Signature, error) {
+	a, err := lookupAlgorithm(s.Algorithm)
+	if err != nil {
+		return algorithm{}, nil, err
+	}
+
+	h := s.Protected
+	h.Algorithm = s.Algorithm
+	if h.KeyID == "" && (s.Unprotected == nil || s.Unprotected.KeyID == "") {
+		h.KeyID = s.Key.ID()
+	}
+	if h.Base64 != nil && !slices.Contains(h.Critical, HeaderB64) {
+		h.Critical = append(slices.Clip(h.Critical), HeaderB64)
+	}
+	if err := h.validateCritical(); err != nil {
+		return algorithm{}, nil, err
+	}
+	if _, err := h.merge(s.Unprotected); err != nil {
+		return algorithm{}, nil, err
+	}
+
+	hb, err := h.MarshalJSON()
+	if err != nil {
+		return algorithm{}, nil, err
+	}
+
+	return a, &Signature{
+		Protected:        h,
+		Unprotected:      s.Unprotected,
+		encodedProtected: []byte(base64.RawURLEncoding.EncodeToString(hb)),
+	}, nil
+}
+
+// ParseJSON parses a JWS in general or flattened JSON serialization without verifying it.
+// A message without payload is detached and is verified with VerifyDetached.
+func ParseJSON(data []byte) (*Message, error) {
+	var raw jsonMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, malformedErr("message", err)
+	}
+
+	signatures := raw.Signatures
+	switch {
+	case raw.Signature != nil && len(signatures) > 0:
+		return nil, malformedErr("message", errors.New("both flattened and general serialization"))
+	case raw.Signature != nil:
+		signatures = []jsonSignature{{Protected: raw.Protected, Header: raw.Header, Signature: *raw.Signature}}
+	case len(signatures) == 0:
+		return nil, malformedErr("message", errors.New("missing signatures"))
+	}
+
+	m := &Message{
+		signatures: make([]*Signature, 0, len(signatures)),
+		detached:   raw.Payload == nil,
+	}
+	for i, s := range signatures {
+		sig, err := parseSignature(s)
+		if err != nil {
+			return nil, fmt.Errorf("signature %d: %w", i, err)
+		}
+		if i > 0 && sig.Protected.unencoded() != m.signatures[0].Protected.unencoded() {
+			return nil, malformedErr("header", fmt.Errorf("%s must be the same for all signatures", HeaderB64))
+		}
+		m.signatures = append(m.signatures, sig)
+	}
+
+	if m.detached {
+		return m, nil
+	}
+
+	m.encodedPayload = []byte(*raw.Payload)
+	if m.signatures[0].Protected.unencoded() {
+		m.payload = m.encodedPayload
+		return m, nil
+	}
+
+	var err error
+	m.payload, err = base64.RawURLEncoding.DecodeString(*raw.Payload)
+	if err != nil {
+		return nil, malformedErr("payload", err)
+	}
+	return m, nil
+}
+
+func parseSignature(s jsonSignature) (*Signature, error) {
+	sig := &Signature{
+		Unprotected:      s.Header,
+		encodedProtected: []byte(s.Protected),
+	}
+
+	if s.Protected != "" {
+		hb, err := base64.RawURLEncoding.DecodeString(s.Protected)
+		if err != nil {
+			return nil, malformedErr("header", err)
+		}
+		if err := json.Unmarshal(hb, &sig.Protected); err != nil {
+			return nil, malformedErr("header", err)
+		}
+	}
+	if _, err := sig.Protected.merge(s.Header); err != nil {
+		return nil, err
+	}
+
+	var err error
+	sig.value, err = base64.RawURLEncoding.DecodeString(s.Signature)
+	if err != nil {
+		return nil, malformedErr("signature", err)
+	}
+	return sig, nil
+}
+
+// VerifyJSON verifies the JWS in general or flattened JSON serialization and returns the payload.
+func VerifyJSON(data []byte, keys KeyProvider, opts ...VerifyOption) ([]byte, error) {
+	m, err := ParseJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return m.Verify(keys, opts...)
+}
+
+// Verify verifies the signatures of the message with the keys returned by the key provider
+// and returns the payload. By default one valid signature is sufficient,
+// see WithAllSignatures and WithRequiredKeyIDs.
+func (m *Message) Verify(keys KeyProvider, opts ...VerifyOption) ([]byte, error) {
+	if m.detached {
+		return nil, malformedErr("payload", errors.New("payload is detached"))
+	}
+
+	if err := m.verify(bytes.NewReader(m.encodedPayload), false, keys, newVerifyConfig(opts)); err != nil {
+		return nil, err
+	}
+	return bytes.Clone(m.payload), nil
+}
+
+// VerifyDetached verifies the signatures of a detached message against the payload
+// read from r, see Verify. The payload is read once and streamed into the hash of
+// every signature. It is only buffered if one of the signatures uses EdDSA.
+func (m *Message) VerifyDetached(r io.Reader, keys KeyProvider, opts ...VerifyOption) error {
+	if !m.detached {
+		return malformedErr("payload", errors.New("payload is not detached"))
+	}
+	return m.verify(r, !m.signatures[0].Protected.unencoded(), keys, newVerifyConfig(opts))
+}
+
+// verify verifies the signatures over the payload read from r, which is encoded if encode is set.
+func (m *Message) verify(r io.Reader, encode bool, keys KeyProvider, cfg verifyConfig) error {
+	var (
+		errs   []error
+		valid  []string
+		kids   []string
+		sigs   []int
+		inputs []*streamedInput
+	)
+	fail := func(i int, err error) error {
+		err = fmt.Errorf("signature %d: %w", i, err)
+		if cfg.all {
+			return err
+		}
+		errs = append(errs, err)
+		return nil
+	}
+
+	for i, sig := range m.signatures {
+		h, err := sig.Header()
+		if err != nil {
+			return err
+		}
+		if len(cfg.kids) > 0 && !slices.Contains(cfg.kids, h.KeyID) {
+			continue
+		}
+
+		a, key, err := verificationAlgorithm(&h, keys, cfg)
+		if err != nil {
+			if err := fail(i, err); err != nil {
+				return err
+			}
+			continue
+		}
+		material, err := a.verificationKey(key)
+		if err != nil {
+			if err := fail(i, err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// The key ID of the verifying key is counted, as the header may be unprotected
+		kids = append(kids, key.ID())
+		sigs = append(sigs, i)
+		inputs = append(inputs, &streamedInput{a: a, material: material, prefix: sig.signingPrefix()})
+	}
+
+	if len(inputs) > 0 {
+		if err := digestInputs(inputs, r, encode); err != nil {
+			return err
+		}
+	}
+	for j, in := range inputs {
+		i := sigs[j]
+		if err := in.a.verifyDigest(in.material, in.input, in.digest, m.signatures[i].value); err != nil {
+			if err := fail(i, err); err != nil {
+				return err
+			}
+			continue
+		}
+		if !slices.Contains(valid, kids[j]) {
+			valid = append(valid, kids[j])
+		}
+	}
+
+	if len(cfg.kids) > 0 {
+		if err := cfg.checkKeyIDs(valid); err != nil {
+			return errors.Join(append([]error{err}, errs...)...)
+		}
+	} else if len(valid) == 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// Payload returns the payload of the message without verifying it,
+// or nil if the message is detached.
+func (m *Message) Payload() []byte {
+	return bytes.Clone(m.payload)
+}
+
+// Signatures returns the signatures of the message.
+func (m *Message) Signatures() []*Signature {
+	return slices.Clone(m.signatures)
+}
+
+// MarshalJSON encodes the message in general JSON serialization.
+func (m *Message) MarshalJSON() ([]byte, error) {
+	raw := jsonMessage{
+		Payload:    m.jsonPayload(),
+		Signatures: make([]jsonSignature, 0, len(m.signatures)),
+	}
+	for _, sig := range m.signatures {
+		raw.Signatures = append(raw.Signatures, sig.toJSON())
+	}
+	return json.Marshal(raw)
+}
+
+// MarshalFlattenedJSON encodes the message in flattened JSON serialization.
+// The message must have exactly one signature.
+func (m *Message) MarshalFlattenedJSON() ([]byte, error) {
+	if len(m.signatures) != 1 {
+		return nil, fmt.Errorf("flattened serialization requires one signature, message has %d", len(m.signatures))
+	}
+
+	sig := m.signatures[0].toJSON()
+	return json.Marshal(jsonMessage{
+		Payload:   m.jsonPayload(),
+		Protected: sig.Protected,
+		Header:    sig.Header,
+		Signature: &sig.Signature,
+	})
+}
+
+// jsonPayload returns the encoded payload, or nil if the message is detached.
+func (m *Message) jsonPayload() *string {
+	if m.detached {
+		return nil
+	}
+	payload := string(m.encodedPayload)
+	return &payload
+}
+
+// signingInput returns the input of the signature (RFC 7515, Section 5.1).
+func (m *Message) signingInput(sig *Signature) []byte {
+	buf := pool.GetBytesBuffer()
+	defer pool.PutBytesBuffer(buf)
+
+	buf.Write(sig.encodedProtected)
+	buf.WriteByte('.')
+	buf.Write(m.encodedPayload)
+	return bytes.Clone(buf.Bytes())
+}
+
+// signingPrefix returns the part of the signing input before the payload.
+func (s *Signature) signingPrefix() []byte {
+	return append(slices.Clip(s.encodedProtected), '.')
+}
+
+// Header returns the union of the protected and unprotected header (RFC 7515, Section 7.2.1).
+func (s *Signature) Header() (Header, error) {
+	return s.Protected.merge(s.Unprotected)
+}
+
+// Value returns the signature value.
+func (s *Signature) Value() []byte {
+	return bytes.Clone(s.value)
+}
+
+func (s *Signature) toJSON() jsonSignature {
+	return jsonSignature{
+		Protected: string(s.encodedProtected),
+		Header:    s.Unprotected,
+		Signature: base64.RawURLEncoding.EncodeToString(s.value),
+	}
+}
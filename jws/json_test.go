@@ -0,0 +1,261 @@
+package jws_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/jgraeger/jwgo"
+	"github.com/jgraeger/jwgo/jwa"
+	"github.com/jgraeger/jwgo/jwk"
+	"github.com/jgraeger/jwgo/jws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignVerifyJSON(t *testing.T) {
+	t.Parallel()
+
+	payload := []byte(`{"version":"1.2.3","sha256":"e3b0c44298fc1c149afbf4c8996fb924"}`)
+	oldKey := mustGenerate(t, jwk.RSA, jwk.WithKeyID("old"))
+	newKey := mustGenerate(t, jwk.EC, jwk.WithKeyID("new"))
+	keys := jws.KeyFromSet(jwk.NewSet(oldKey, newKey))
+
+	m, err := jws.SignJSON(payload,
+		jws.Signer{Algorithm: jwa.RS256, Key: oldKey, Protected: jws.Header{Type: "manifest"}},
+		jws.Signer{Algorithm: jwa.ES256, Key: newKey, Unprotected: &jws.Header{KeyID: "new"}},
+	)
+	require.NoError(t, err)
+
+	general, err := m.MarshalJSON()
+	require.NoError(t, err)
+
+	var members map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(general, &members))
+	assert.Contains(t, members, "signatures")
+	assert.NotContains(t, members, "signature")
+
+	parsed, err := jws.ParseJSON(general)
+	require.NoError(t, err)
+	assert.Equal(t, payload, parsed.Payload())
+	require.Len(t, parsed.Signatures(), 2)
+
+	first := parsed.Signatures()[0]
+	assert.Equal(t, jws.Header{Algorithm: jwa.RS256, KeyID: "old", Type: "manifest"}, first.Protected)
+	assert.Nil(t, first.Unprotected)
+
+	second := parsed.Signatures()[1]
+	assert.Equal(t, jws.Header{Algorithm: jwa.ES256}, second.Protected)
+	assert.Equal(t, &jws.Header{KeyID: "new"}, second.Unprotected)
+	h, err := second.Header()
+	require.NoError(t, err)
+	assert.Equal(t, jws.Header{Algorithm: jwa.ES256, KeyID: "new"}, h)
+
+	for _, opts := range [][]jws.VerifyOption{
+		nil,
+		{jws.WithAllSignatures()},
+		{jws.WithRequiredKeyIDs("old", "new")},
+		{jws.WithRequiredKeyIDs("new")},
+	} {
+		verified, err := jws.VerifyJSON(general, keys, opts...)
+		require.NoError(t, err)
+		assert.Equal(t, payload, verified)
+	}
+
+	// Only the new key is known
+	onlyNew := jws.KeyFromSet(jwk.NewSet(newKey))
+	_, err = jws.VerifyJSON(general, onlyNew)
+	require.NoError(t, err)
+	_, err = jws.VerifyJSON(general, onlyNew, jws.WithAllSignatures())
+	assert.ErrorIs(t, err, jwk.ErrKeyNotFound)
+	_, err = jws.VerifyJSON(general, onlyNew, jws.WithRequiredKeyIDs("old", "new"))
+	assert.ErrorIs(t, err, jws.ErrMissingSignature)
+	_, err = jws.VerifyJSON(general, keys, jws.WithRequiredKeyIDs("other"))
+	assert.ErrorIs(t, err, jws.ErrMissingSignature)
+
+	// Flattened serialization
+	_, err = m.MarshalFlattenedJSON()
+	assert.Error(t, err)
+
+	single, err := jws.SignJSON(payload, jws.Signer{Algorithm: jwa.ES256, Key: newKey})
+	require.NoError(t, err)
+	flattened, err := single.MarshalFlattenedJSON()
+	require.NoError(t, err)
+
+	members = nil
+	require.NoError(t, json.Unmarshal(flattened, &members))
+	assert.Contains(t, members, "signature")
+	assert.NotContains(t, members, "signatures")
+
+	verified, err := jws.VerifyJSON(flattened, keys, jws.WithRequiredKeyIDs("new"))
+	require.NoError(t, err)
+	assert.Equal(t, payload, verified)
+}
+
+func TestVerifyJSONForgedKeyID(t *testing.T) {
+	t.Parallel()
+
+	a := mustGenerate(t, jwk.Oct, jwk.WithKeyID("a"))
+	m, err := jws.SignJSON([]byte("payload"), jws.Signer{Algorithm: jwa.HS256, Key: a, Unprotected: &jws.Header{KeyID: "a"}})
+	require.NoError(t, err)
+	general, err := m.MarshalJSON()
+	require.NoError(t, err)
+
+	// The signature by a is copied with an unprotected header claiming another key
+	var raw struct {
+		Payload    string                       `json:"payload"`
+		Signatures []map[string]json.RawMessage `json:"signatures"`
+	}
+	require.NoError(t, json.Unmarshal(general, &raw))
+	forged := map[string]json.RawMessage{
+		"protected": raw.Signatures[0]["protected"],
+		"header":    json.RawMessage(`{"kid":"b"}`),
+		"signature": raw.Signatures[0]["signature"],
+	}
+	raw.Signatures = append(raw.Signatures, forged)
+	data, err := json.Marshal(raw)
+	require.NoError(t, err)
+
+	_, err = jws.VerifyJSON(data, jws.StaticKey(a), jws.WithRequiredKeyIDs("a"))
+	require.NoError(t, err)
+	_, err = jws.VerifyJSON(data, jws.StaticKey(a), jws.WithRequiredKeyIDs("a", "b"))
+	assert.ErrorIs(t, err, jws.ErrMissingSignature)
+	_, err = jws.VerifyJSON(data, jws.StaticKey(a), jws.WithRequiredKeyIDs("b"))
+	assert.ErrorIs(t, err, jws.ErrMissingSignature)
+}
+
+func TestVerifyJSONTestVectors(t *testing.T) {
+	t.Parallel()
+
+	// RFC 7515, Appendix A.7
+	flattened := `{
+		"payload": "eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ",
+		"protected": "eyJhbGciOiJFUzI1NiJ9",
+		"header": {"kid": "e9bc097a-ce51-4036-9562-d2ade882db0d"},
+		"signature": "DtEhU3ljbEg8L38VWAfUAqOyKAM6-Xx-F4GawxaepmXFCgfTjDxw5djxLa8ISlSApmWQxfKTUJqPP3-Kg6NU1Q"
+	}`
+	key, err := jwk.ParseString(`{"kty":"EC","kid":"e9bc097a-ce51-4036-9562-d2ade882db0d","crv":"P-256","x":"f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU","y":"x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0"}`)
+	require.NoError(t, err)
+
+	payload, err := jws.VerifyJSON([]byte(flattened), jws.KeyFromSet(jwk.NewSet(key)))
+	require.NoError(t, err)
+	assert.Equal(t, "{\"iss\":\"joe\",\r\n \"exp\":1300819380,\r\n \"http://example.com/is_root\":true}", string(payload))
+
+	// The encoded protected header is kept, so the message can be verified after reencoding
+	m, err := jws.ParseJSON([]byte(flattened))
+	require.NoError(t, err)
+	general, err := m.MarshalJSON()
+	require.NoError(t, err)
+	_, err = jws.VerifyJSON(general, jws.StaticKey(key))
+	require.NoError(t, err)
+
+	// Detached payload (RFC 7515, Appendix F)
+	var members map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal([]byte(flattened), &members))
+	delete(members, "payload")
+	detached, err := json.Marshal(members)
+	require.NoError(t, err)
+
+	m, err = jws.ParseJSON(detached)
+	require.NoError(t, err)
+	assert.Nil(t, m.Payload())
+	require.NoError(t, m.VerifyDetached(bytes.NewReader(payload), jws.StaticKey(key)))
+}
+
+func TestSignVerifyJSONDetached(t *testing.T) {
+	t.Parallel()
+
+	// Large enough to be copied in several chunks, and neither valid UTF-8 nor free of '.'
+	payload := make([]byte, 3<<20)
+	_, err := rand.Read(payload)
+	require.NoError(t, err)
+
+	hmacKey := mustGenerate(t, jwk.Oct, jwk.WithKeyID("hmac"))
+	ecKey := mustGenerate(t, jwk.EC, jwk.WithKeyID("ec"))
+	okpKey := mustGenerate(t, jwk.OKP, jwk.WithKeyID("okp"))
+	keys := jws.KeyFromSet(jwk.NewSet(hmacKey, ecKey, okpKey))
+	b64 := false
+
+	for _, tt := range []struct {
+		name      string
+		protected jws.Header
+	}{
+		{name: "encoded"},
+		{name: "unencoded", protected: jws.Header{Base64: &b64}},
+	} {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			m, err := jws.SignJSONDetached(bytes.NewReader(payload),
+				jws.Signer{Algorithm: jwa.HS256, Key: hmacKey, Protected: tc.protected},
+				jws.Signer{Algorithm: jwa.ES256, Key: ecKey, Protected: tc.protected},
+				jws.Signer{Algorithm: jwa.EdDSA, Key: okpKey, Protected: tc.protected},
+			)
+			require.NoError(t, err)
+			assert.Nil(t, m.Payload())
+
+			general, err := m.MarshalJSON()
+			require.NoError(t, err)
+			var members map[string]json.RawMessage
+			require.NoError(t, json.Unmarshal(general, &members))
+			assert.NotContains(t, members, "payload")
+
+			parsed, err := jws.ParseJSON(general)
+			require.NoError(t, err)
+			require.NoError(t, parsed.VerifyDetached(bytes.NewReader(payload), keys,
+				jws.WithAllSignatures(), jws.WithRequiredKeyIDs("hmac", "ec", "okp")))
+
+			err = parsed.VerifyDetached(io.LimitReader(bytes.NewReader(payload), int64(len(payload)-1)), keys)
+			assert.ErrorIs(t, err, jws.ErrInvalidSignature)
+			_, err = parsed.Verify(keys)
+			assert.ErrorIs(t, err, jwgo.ErrTokenMalformed)
+			_, err = jws.VerifyJSON(general, keys)
+			assert.ErrorIs(t, err, jwgo.ErrTokenMalformed)
+		})
+	}
+
+	// A flattened detached message
+	m, err := jws.SignJSONDetached(bytes.NewReader(payload), jws.Signer{Algorithm: jwa.ES256, Key: ecKey})
+	require.NoError(t, err)
+	flattened, err := m.MarshalFlattenedJSON()
+	require.NoError(t, err)
+	parsed, err := jws.ParseJSON(flattened)
+	require.NoError(t, err)
+	require.NoError(t, parsed.VerifyDetached(bytes.NewReader(payload), keys))
+
+	// An attached message is not verified against another payload
+	m, err = jws.SignJSON([]byte("payload"), jws.Signer{Algorithm: jwa.ES256, Key: ecKey})
+	require.NoError(t, err)
+	err = m.VerifyDetached(strings.NewReader("payload"), keys)
+	assert.ErrorIs(t, err, jwgo.ErrTokenMalformed)
+}
+
+func TestParseJSONErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name string
+		data string
+	}{
+		{name: "not JSON", data: `eyJhbGciOiJFUzI1NiJ9.e30.c2ln`},
+		{name: "missing signatures", data: `{"payload":"e30"}`},
+		{name: "empty signatures", data: `{"payload":"e30","signatures":[]}`},
+		{name: "general and flattened", data: `{"payload":"e30","signature":"c2ln","signatures":[{"signature":"c2ln"}]}`},
+		{name: "malformed payload", data: `{"payload":"!","signature":"c2ln"}`},
+		{name: "malformed protected header", data: `{"payload":"e30","protected":"!","signature":"c2ln"}`},
+		{name: "malformed signature", data: `{"payload":"e30","protected":"eyJhbGciOiJFUzI1NiJ9","signature":"!"}`},
+		{name: "duplicate header parameter", data: `{"payload":"e30","protected":"eyJhbGciOiJFUzI1NiJ9","header":{"alg":"ES256"},"signature":"c2ln"}`},
+	} {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := jws.ParseJSON([]byte(tc.data))
+			assert.ErrorIs(t, err, jwgo.ErrTokenMalformed)
+		})
+	}
+}
@@ -0,0 +1,231 @@
+// Package jws implements JSON Web Signatures as defined in RFC 7515.
+package jws
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+
+	"github.com/goccy/go-json"
+	"github.com/jgraeger/jwgo/internal/base64"
+	"github.com/jgraeger/jwgo/internal/pool"
+	"github.com/jgraeger/jwgo/jwa"
+	"github.com/jgraeger/jwgo/jwk"
+)
+
+// Sign signs the payload with the key and returns the JWS in compact serialization.
+// The `kid` header is set to the ID of the key, unless set by WithKeyID.
+func Sign(payload []byte, alg jwa.SignatureAlgorithm, key jwk.Key, opts ...SignOption) ([]byte, error) {
+	cfg := newSignConfig(opts)
+
+	a, err := lookupAlgorithm(alg)
+	if err != nil {
+		return nil, err
+	}
+
+	// An unencoded payload must not contain the separator (RFC 7797, Section 5.2)
+	if cfg.unencoded && bytes.IndexByte(payload, '.') >= 0 {
+		return nil, errors.New("unencoded payload must not contain '.', use SignDetached instead")
+	}
+
+	buf := pool.GetBytesBuffer()
+	defer pool.PutBytesBuffer(buf)
+
+	if err := cfg.writeHeader(buf, alg, key); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('.')
+	if cfg.unencoded {
+		buf.Write(payload)
+	} else {
+		writeBase64(buf, payload)
+	}
+
+	sig, err := a.sign(key, buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteByte('.')
+	writeBase64(buf, sig)
+
+	return bytes.Clone(buf.Bytes()), nil
+}
+
+// SignDetached signs the payload read from r and returns the JWS in compact
+// serialization without payload (RFC 7515, Appendix F). The payload is streamed
+// into the hash, except for EdDSA, which signs the whole input.
+func SignDetached(r io.Reader, alg jwa.SignatureAlgorithm, key jwk.Key, opts ...SignOption) ([]byte, error) {
+	cfg := newSignConfig(opts)
+
+	a, err := lookupAlgorithm(alg)
+	if err != nil {
+		return nil, err
+	}
+	material, err := a.signingKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := pool.GetBytesBuffer()
+	defer pool.PutBytesBuffer(buf)
+
+	if err := cfg.writeHeader(buf, alg, key); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('.')
+
+	input, digest, err := a.digestReader(material, buf.Bytes(), r, !cfg.unencoded)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := a.signDigest(material, input, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteByte('.')
+	writeBase64(buf, sig)
+
+	return bytes.Clone(buf.Bytes()), nil
+}
+
+// Verify verifies the JWS in compact serialization with the key returned by the
+// key provider and returns the payload.
+func Verify(token []byte, keys KeyProvider, opts ...VerifyOption) ([]byte, error) {
+	cfg := newVerifyConfig(opts)
+
+	header, payload, signature, err := splitCompact(token)
+	if err != nil {
+		return nil, err
+	}
+
+	h, sig, err := decodeCompact(header, signature)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfg.kids) > 0 && !slices.Contains(cfg.kids, h.KeyID) {
+		return nil, cfg.checkKeyIDs(nil)
+	}
+	key, err := verifySignature(&h, token[:len(header)+1+len(payload)], sig, keys, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.checkKeyIDs([]string{key.ID()}); err != nil {
+		return nil, err
+	}
+
+	if h.unencoded() {
+		return bytes.Clone(payload), nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(string(payload))
+	if err != nil {
+		return nil, malformedErr("payload", err)
+	}
+	return decoded, nil
+}
+
+// VerifyDetached verifies the JWS in compact serialization without payload
+// against the payload read from r. The payload is streamed into the hash,
+// except for EdDSA, which verifies the whole input.
+func VerifyDetached(token []byte, r io.Reader, keys KeyProvider, opts ...VerifyOption) error {
+	cfg := newVerifyConfig(opts)
+
+	header, payload, signature, err := splitCompact(token)
+	if err != nil {
+		return err
+	} else if len(payload) > 0 {
+		return malformedErr("payload", errors.New("payload is not detached"))
+	}
+
+	h, sig, err := decodeCompact(header, signature)
+	if err != nil {
+		return err
+	}
+
+	if len(cfg.kids) > 0 && !slices.Contains(cfg.kids, h.KeyID) {
+		return cfg.checkKeyIDs(nil)
+	}
+	a, key, err := verificationAlgorithm(&h, keys, cfg)
+	if err != nil {
+		return err
+	}
+	material, err := a.verificationKey(key)
+	if err != nil {
+		return err
+	}
+
+	input, digest, err := a.digestReader(material, token[:len(header)+1], r, !h.unencoded())
+	if err != nil {
+		return err
+	}
+	if err := a.verifyDigest(material, input, digest, sig); err != nil {
+		return err
+	}
+	return cfg.checkKeyIDs([]string{key.ID()})
+}
+
+// decodeCompact decodes the header and signature segments of a JWS in compact serialization.
+func decodeCompact(header, signature []byte) (Header, []byte, error) {
+	var h Header
+	hb, err := base64.RawURLEncoding.DecodeString(string(header))
+	if err != nil {
+		return h, nil, malformedErr("header", err)
+	}
+	if err := json.Unmarshal(hb, &h); err != nil {
+		return h, nil, malformedErr("header", err)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(string(signature))
+	if err != nil {
+		return h, nil, malformedErr("signature", err)
+	}
+	return h, sig, nil
+}
+
+// verifySignature verifies the signature of the input with the key for the header
+// and returns the key.
+func verifySignature(h *Header, input, sig []byte, keys KeyProvider, cfg verifyConfig) (jwk.Key, error) {
+	a, key, err := verificationAlgorithm(h, keys, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.verify(key, input, sig); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// verificationAlgorithm checks the header and returns the algorithm and key to verify with.
+func verificationAlgorithm(h *Header, keys KeyProvider, cfg verifyConfig) (algorithm, jwk.Key, error) {
+	if err := h.checkCritical(cfg.critical); err != nil {
+		return algorithm{}, nil, err
+	}
+	if !cfg.allows(h.Algorithm) {
+		return algorithm{}, nil, unsupportedAlgorithmErr(h.Algorithm)
+	}
+	a, err := lookupAlgorithm(h.Algorithm)
+	if err != nil {
+		return algorithm{}, nil, err
+	}
+
+	key, err := keys.Key(h)
+	if err != nil {
+		return algorithm{}, nil, err
+	}
+	// Required key IDs are counted by the verifying key, which must be the key the signature claims
+	if len(cfg.kids) > 0 && h.KeyID != "" && h.KeyID != key.ID() {
+		return algorithm{}, nil, keyNotFoundErr(h.KeyID)
+	}
+	return a, key, nil
+}
+
+func writeBase64(buf *bytes.Buffer, b []byte) {
+	n := base64.RawURLEncoding.EncodedLen(len(b))
+	buf.Grow(n)
+	dst := buf.AvailableBuffer()[:n]
+	base64.RawURLEncoding.Encode(dst, b)
+	buf.Write(dst)
+}
@@ -0,0 +1,45 @@
+package jws
+
+import (
+	"fmt"
+
+	"github.com/jgraeger/jwgo/jwk"
+)
+
+// KeyProvider returns the key to verify a JWS with.
+type KeyProvider interface {
+	Key(h *Header) (jwk.Key, error)
+}
+
+// KeyProviderFunc is a function that implements KeyProvider.
+type KeyProviderFunc func(h *Header) (jwk.Key, error)
+
+func (f KeyProviderFunc) Key(h *Header) (jwk.Key, error) {
+	return f(h)
+}
+
+// StaticKey returns a key provider that always returns the key.
+func StaticKey(k jwk.Key) KeyProvider {
+	return KeyProviderFunc(func(*Header) (jwk.Key, error) {
+		return k, nil
+	})
+}
+
+// KeyFromSet returns a key provider that looks up the key by the `kid` header.
+// If the header has no key ID, the set must contain exactly one key.
+func KeyFromSet(set *jwk.Set) KeyProvider {
+	return KeyProviderFunc(func(h *Header) (jwk.Key, error) {
+		if h.KeyID == "" {
+			if keys := set.Keys(); len(keys) == 1 {
+				return keys[0], nil
+			}
+			return nil, fmt.Errorf("%w: header has no kid", jwk.ErrKeyNotFound)
+		}
+
+		k, ok := set.LookupKeyID(h.KeyID)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", jwk.ErrKeyNotFound, h.KeyID)
+		}
+		return k, nil
+	})
+}
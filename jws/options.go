@@ -0,0 +1,168 @@
+package jws
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/jgraeger/jwgo/jwa"
+	"github.com/jgraeger/jwgo/jwk"
+)
+
+// SignOption configures the signing of a JWS.
+type SignOption func(*signConfig)
+
+type signConfig struct {
+	header    Header
+	kid       *string
+	typ       string
+	cty       string
+	unencoded bool
+}
+
+func newSignConfig(opts []SignOption) signConfig {
+	var cfg signConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// writeHeader writes the encoded protected header.
+func (c signConfig) writeHeader(buf *bytes.Buffer, alg jwa.SignatureAlgorithm, key jwk.Key) error {
+	h := c.header
+	h.Algorithm = alg
+	if c.kid != nil {
+		h.KeyID = *c.kid
+	} else if h.KeyID == "" {
+		h.KeyID = key.ID()
+	}
+	if c.typ != "" {
+		h.Type = c.typ
+	}
+	if c.cty != "" {
+		h.ContentType = c.cty
+	}
+	if c.unencoded {
+		h.Base64 = new(bool)
+	}
+	if h.Base64 != nil && !slices.Contains(h.Critical, HeaderB64) {
+		h.Critical = append(slices.Clip(h.Critical), HeaderB64)
+	}
+	if err := h.validateCritical(); err != nil {
+		return err
+	}
+
+	hb, err := h.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	writeBase64(buf, hb)
+	return nil
+}
+
+// WithHeader sets the protected header. The `alg` header is always set to the
+// signature algorithm, the other options override the members of the header.
+func WithHeader(h Header) SignOption {
+	return func(c *signConfig) {
+		c.header = h
+	}
+}
+
+// WithKeyID sets the `kid` header. An empty key ID omits the header.
+func WithKeyID(kid string) SignOption {
+	return func(c *signConfig) {
+		c.kid = &kid
+	}
+}
+
+// WithType sets the `typ` header, e.g. JWT.
+func WithType(typ string) SignOption {
+	return func(c *signConfig) {
+		c.typ = typ
+	}
+}
+
+// WithContentType sets the `cty` header.
+func WithContentType(cty string) SignOption {
+	return func(c *signConfig) {
+		c.cty = cty
+	}
+}
+
+// WithUnencodedPayload signs the payload without base64url encoding it (RFC 7797).
+// The `b64` header is set to false and marked as critical.
+func WithUnencodedPayload() SignOption {
+	return func(c *signConfig) {
+		c.unencoded = true
+	}
+}
+
+// VerifyOption configures the verification of a JWS.
+type VerifyOption func(*verifyConfig)
+
+type verifyConfig struct {
+	algs     []jwa.SignatureAlgorithm
+	all      bool
+	kids     []string
+	critical map[string]CriticalHandler
+}
+
+func newVerifyConfig(opts []VerifyOption) verifyConfig {
+	var cfg verifyConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// allows reports whether the algorithm is allowed.
+func (c verifyConfig) allows(alg jwa.SignatureAlgorithm) bool {
+	return len(c.algs) == 0 || slices.Contains(c.algs, alg)
+}
+
+// checkKeyIDs checks that a signature was verified for each required key ID.
+func (c verifyConfig) checkKeyIDs(verified []string) error {
+	for _, kid := range c.kids {
+		if !slices.Contains(verified, kid) {
+			return missingSignatureErr(kid)
+		}
+	}
+	return nil
+}
+
+// WithAllowedAlgorithms restricts the algorithms accepted for verification.
+// By default all supported algorithms are accepted, but the `alg` claim of
+// the key must match the algorithm if it is set.
+func WithAllowedAlgorithms(algs ...jwa.SignatureAlgorithm) VerifyOption {
+	return func(c *verifyConfig) {
+		c.algs = algs
+	}
+}
+
+// WithAllSignatures requires all signatures of a JWS in JSON serialization to be valid.
+// By default one valid signature is sufficient.
+func WithAllSignatures() VerifyOption {
+	return func(c *verifyConfig) {
+		c.all = true
+	}
+}
+
+// WithRequiredKeyIDs requires a valid signature for each of the key IDs.
+// A signature counts for the ID of the key returned by the key provider, which must
+// match the `kid` header of the signature. Signatures by other keys are ignored.
+func WithRequiredKeyIDs(kids ...string) VerifyOption {
+	return func(c *verifyConfig) {
+		c.kids = kids
+	}
+}
+
+// WithCriticalHandler registers a handler for a critical header extension.
+// Tokens that list extensions without handler as critical are rejected.
+func WithCriticalHandler(name string, handler CriticalHandler) VerifyOption {
+	return func(c *verifyConfig) {
+		if c.critical == nil {
+			c.critical = make(map[string]CriticalHandler)
+		}
+		c.critical[name] = handler
+	}
+}
@@ -0,0 +1,175 @@
+package jws
+
+import (
+	"bytes"
+	"hash"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/goccy/go-json"
+	"github.com/jgraeger/jwgo/internal/base64"
+	"github.com/jgraeger/jwgo/internal/pool"
+	"github.com/jgraeger/jwgo/jwa"
+	"github.com/jgraeger/jwgo/jwk"
+)
+
+// Verifier verifies JWS in compact serialization with a fixed algorithm and key.
+//
+// It is intended for hot paths: the token is split in place, the signature is
+// decoded into pooled buffers and the protected header of the last verified token
+// is cached, so tokens with the same header are not decoded again. HMAC and EdDSA
+// verification do not allocate, RSA and ECDSA only allocate within the standard library.
+// A Verifier is safe for concurrent use.
+type Verifier struct {
+	a        algorithm
+	kid      string
+	material any
+	critical map[string]CriticalHandler
+
+	states sync.Pool
+	// header is the protected header of the last verified token
+	header atomic.Pointer[acceptedHeader]
+}
+
+// acceptedHeader is an encoded protected header that passed the checks.
+type acceptedHeader struct {
+	segment   []byte
+	unencoded bool
+}
+
+// verifyState holds the buffers used by a single verification.
+type verifyState struct {
+	hash    hash.Hash
+	sig     []byte
+	payload []byte
+	digest  [64]byte
+}
+
+// NewVerifier creates a verifier for tokens signed with the algorithm and key.
+// Private keys are converted to their public key. Of the options, only
+// critical header handlers apply. Headers with critical extensions are not
+// cached, so their handlers are called for every token.
+func NewVerifier(alg jwa.SignatureAlgorithm, key jwk.Key, opts ...VerifyOption) (*Verifier, error) {
+	cfg := newVerifyConfig(opts)
+
+	a, err := lookupAlgorithm(alg)
+	if err != nil {
+		return nil, err
+	}
+
+	material, err := a.verificationKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Verifier{a: a, kid: key.ID(), material: material, critical: cfg.critical}
+	v.states.New = func() any {
+		return &verifyState{hash: a.newHash(material)}
+	}
+	return v, nil
+}
+
+// Verify verifies the token, appends the payload to dst and returns the extended buffer.
+// No memory is allocated for the payload if dst has enough capacity.
+func (v *Verifier) Verify(dst, token []byte) ([]byte, error) {
+	header, payload, sig, err := splitCompact(token)
+	if err != nil {
+		return dst, err
+	}
+
+	accepted := v.header.Load()
+	known := accepted != nil && bytes.Equal(accepted.segment, header)
+	cacheable := false
+	if !known {
+		h, err := v.checkHeader(header)
+		if err != nil {
+			return dst, err
+		}
+		accepted = &acceptedHeader{segment: bytes.Clone(header), unencoded: h.unencoded()}
+		cacheable = !h.hasCriticalExtensions()
+	}
+
+	//nolint:forcetypeassert
+	s := v.states.Get().(*verifyState)
+	defer v.states.Put(s)
+
+	s.sig, err = decodeBase64(s.sig, sig)
+	if err != nil {
+		return dst, malformedErr("signature", err)
+	}
+
+	input := token[:len(header)+1+len(payload)]
+	var digest []byte
+	if s.hash != nil {
+		s.hash.Reset()
+		s.hash.Write(input)
+		digest = s.hash.Sum(s.digest[:0])
+	}
+	if err := v.a.verifyDigest(v.material, input, digest, s.sig); err != nil {
+		return dst, err
+	}
+
+	if cacheable {
+		v.header.Store(accepted)
+	}
+	if accepted.unencoded {
+		return append(dst, payload...), nil
+	}
+
+	// The decoder needs more space than the decoded payload, so it is decoded into
+	// the pooled buffer first. Otherwise dst would grow even if the payload fits.
+	s.payload, err = decodeBase64(s.payload, payload)
+	if err != nil {
+		return dst, malformedErr("payload", err)
+	}
+	return append(dst, s.payload...), nil
+}
+
+// checkHeader decodes the protected header and checks it against the algorithm and key.
+func (v *Verifier) checkHeader(segment []byte) (*Header, error) {
+	buf := pool.GetBytesBuffer()
+	defer pool.PutBytesBuffer(buf)
+
+	hb, err := decodeBase64(buf.AvailableBuffer(), segment)
+	if err != nil {
+		return nil, malformedErr("header", err)
+	}
+
+	var h Header
+	if err := json.Unmarshal(hb, &h); err != nil {
+		return nil, malformedErr("header", err)
+	}
+
+	if err := h.checkCritical(v.critical); err != nil {
+		return nil, err
+	}
+	if h.Algorithm != v.a.alg {
+		return nil, unsupportedAlgorithmErr(h.Algorithm)
+	}
+	if h.KeyID != "" && v.kid != "" && h.KeyID != v.kid {
+		return nil, keyNotFoundErr(h.KeyID)
+	}
+	return &h, nil
+}
+
+// decodeBase64 decodes the base64url encoded src into buf, growing it if needed.
+func decodeBase64(buf, src []byte) ([]byte, error) {
+	buf = slices.Grow(buf[:0], base64.RawURLEncoding.DecodedLen(len(src)))
+	n, err := base64.RawURLEncoding.Decode(buf[:cap(buf)], src)
+	if err != nil {
+		return buf[:0], err
+	}
+	return buf[:n], nil
+}
+
+// splitCompact splits a JWS in compact serialization into its encoded segments
+// without copying them.
+func splitCompact(token []byte) (header, payload, sig []byte, err error) {
+	first := bytes.IndexByte(token, '.')
+	last := bytes.LastIndexByte(token, '.')
+	if first < 0 || first == last || bytes.IndexByte(token[first+1:last], '.') >= 0 {
+		return nil, nil, nil, errInvalidSegmentCount
+	}
+	return token[:first], token[first+1 : last], token[last+1:], nil
+}
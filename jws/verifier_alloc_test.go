@@ -0,0 +1,50 @@
+//go:build !race
+
+package jws_test
+
+import (
+	"testing"
+
+	"github.com/jgraeger/jwgo/jwa"
+	"github.com/jgraeger/jwgo/jwk"
+	"github.com/jgraeger/jwgo/jws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestVerifierAllocs is not run in parallel, as other tests would be counted.
+// The race detector allocates, so the file is excluded from race builds.
+//
+//nolint:paralleltest
+func TestVerifierAllocs(t *testing.T) {
+	if testing.CoverMode() != "" {
+		t.Skip("coverage instrumentation allocates")
+	}
+
+	payload := []byte(`{"sub":"1234567890","name":"John Doe","iat":1516239022}`)
+
+	for _, tt := range []struct {
+		alg jwa.SignatureAlgorithm
+		key jwk.Key
+	}{
+		{alg: jwa.HS256, key: mustGenerate(t, jwk.Oct)},
+		{alg: jwa.HS512, key: mustGenerate(t, jwk.Oct, jwk.WithAlgorithm(jwa.KeyAlgorithmMustFrom(jwa.HS512)))},
+		{alg: jwa.EdDSA, key: mustGenerate(t, jwk.OKP)},
+	} {
+		tc := tt
+		t.Run(tc.alg.String(), func(t *testing.T) {
+			v, err := jws.NewVerifier(tc.alg, tc.key)
+			require.NoError(t, err)
+			token, err := jws.Sign(payload, tc.alg, tc.key)
+			require.NoError(t, err)
+
+			dst := make([]byte, 0, len(payload))
+			allocs := testing.AllocsPerRun(100, func() {
+				if _, err := v.Verify(dst, token); err != nil {
+					t.Fatal(err)
+				}
+			})
+			assert.Zero(t, allocs)
+		})
+	}
+}
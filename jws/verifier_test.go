@@ -0,0 +1,105 @@
+package jws_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/jgraeger/jwgo"
+	"github.com/jgraeger/jwgo/jwa"
+	"github.com/jgraeger/jwgo/jwk"
+	"github.com/jgraeger/jwgo/jws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifier(t *testing.T) {
+	t.Parallel()
+
+	payload := []byte(`{"iss":"joe","exp":1300819380}`)
+
+	for _, tt := range []struct {
+		alg jwa.SignatureAlgorithm
+		key jwk.Key
+	}{
+		{alg: jwa.HS256, key: mustGenerate(t, jwk.Oct)},
+		{alg: jwa.RS256, key: mustGenerate(t, jwk.RSA)},
+		{alg: jwa.ES256, key: mustGenerate(t, jwk.EC)},
+		{alg: jwa.ES512, key: mustGenerate(t, jwk.EC, jwk.WithCurve(jwk.P521))},
+		{alg: jwa.EdDSA, key: mustGenerate(t, jwk.OKP)},
+	} {
+		tc := tt
+		t.Run(tc.alg.String(), func(t *testing.T) {
+			t.Parallel()
+
+			v, err := jws.NewVerifier(tc.alg, tc.key)
+			require.NoError(t, err)
+
+			token, err := jws.Sign(payload, tc.alg, tc.key)
+			require.NoError(t, err)
+
+			// The second verification uses the cached header
+			for i := 0; i < 2; i++ {
+				verified, err := v.Verify(nil, token)
+				require.NoError(t, err)
+				assert.Equal(t, payload, verified)
+			}
+
+			// The payload is appended to dst
+			verified, err := v.Verify([]byte("prefix"), token)
+			require.NoError(t, err)
+			assert.Equal(t, append([]byte("prefix"), payload...), verified)
+
+			parts := strings.Split(string(token), ".")
+			parts[1] = "eyJpc3MiOiJldmUifQ"
+			_, err = v.Verify(nil, []byte(strings.Join(parts, ".")))
+			assert.ErrorIs(t, err, jws.ErrInvalidSignature)
+		})
+	}
+}
+
+func TestVerifierErrors(t *testing.T) {
+	t.Parallel()
+
+	key := mustGenerate(t, jwk.Oct, jwk.WithKeyID("hmac"))
+	v, err := jws.NewVerifier(jwa.HS256, key)
+	require.NoError(t, err)
+
+	_, err = jws.NewVerifier(jwa.ES256, key)
+	assert.ErrorIs(t, err, jws.ErrInvalidKey)
+	_, err = jws.NewVerifier(jwa.SignatureAlgorithm("none"), key)
+	assert.ErrorIs(t, err, jws.ErrUnsupportedAlgorithm)
+
+	other, err := jwk.FromCrypto(key.Raw(), jwk.WithKeyID("other"))
+	require.NoError(t, err)
+	otherToken, err := jws.Sign([]byte("payload"), jwa.HS256, other)
+	require.NoError(t, err)
+	critToken, err := jws.Sign([]byte("payload"), jwa.HS256, key, jws.WithHeader(jws.Header{
+		Critical:   []string{"exp"},
+		Extensions: map[string]json.RawMessage{"exp": json.RawMessage(`1300819380`)},
+	}))
+	require.NoError(t, err)
+	hs512Token, err := jws.Sign([]byte("payload"), jwa.HS512, mustGenerate(t, jwk.Oct, jwk.WithKeyID("hmac"), jwk.WithAlgorithm(jwa.KeyAlgorithmMustFrom(jwa.HS512))))
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name     string
+		token    string
+		expected error
+	}{
+		{name: "segments", token: "a.b", expected: jwgo.ErrTokenMalformed},
+		{name: "header", token: "!.b.c", expected: jwgo.ErrTokenMalformed},
+		{name: "signature", token: "eyJhbGciOiJIUzI1NiJ9.e30.!", expected: jwgo.ErrTokenMalformed},
+		{name: "algorithm", token: string(hs512Token), expected: jws.ErrUnsupportedAlgorithm},
+		{name: "key ID", token: string(otherToken), expected: jwk.ErrKeyNotFound},
+		{name: "unknown critical header", token: string(critToken), expected: jws.ErrUnsupportedCritical},
+	} {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := v.Verify(nil, []byte(tc.token))
+			assert.ErrorIs(t, err, tc.expected)
+		})
+	}
+}